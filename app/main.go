@@ -1,21 +1,61 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
 	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
+	_instrumentCache "github.com/bxcodec/go-clean-arch/cache/instrument"
+	_memoryCache "github.com/bxcodec/go-clean-arch/cache/memory"
+	_redisCache "github.com/bxcodec/go-clean-arch/cache/redis"
+	_clientRepo "github.com/bxcodec/go-clean-arch/client/repository/mysql"
+	_clientUcase "github.com/bxcodec/go-clean-arch/client/usecase"
+	"github.com/bxcodec/go-clean-arch/domain"
+	_healthChecker "github.com/bxcodec/go-clean-arch/health/checker"
+	_healthHttpDelivery "github.com/bxcodec/go-clean-arch/health/delivery/http"
+	_healthUcase "github.com/bxcodec/go-clean-arch/health/usecase"
+	"github.com/bxcodec/go-clean-arch/jwt"
+	"github.com/bxcodec/go-clean-arch/lifecycle"
+	_logmovieHttpDelivery "github.com/bxcodec/go-clean-arch/logmovie/delivery/http"
+	_logmovieBatchRepo "github.com/bxcodec/go-clean-arch/logmovie/repository/batch"
+	_logmovieInstrumentRepo "github.com/bxcodec/go-clean-arch/logmovie/repository/instrument"
 	_logmovieRepo "github.com/bxcodec/go-clean-arch/logmovie/repository/mysql"
+	_logmovieUcase "github.com/bxcodec/go-clean-arch/logmovie/usecase"
 	_movieHttpDelivery "github.com/bxcodec/go-clean-arch/movie/delivery/http"
 	_movieHttpDeliveryMiddleware "github.com/bxcodec/go-clean-arch/movie/delivery/http/middleware"
+	_movieAggregateRepo "github.com/bxcodec/go-clean-arch/movie/repository/aggregate"
+	_movieCacheRepo "github.com/bxcodec/go-clean-arch/movie/repository/cache"
+	_movieCompositeRepo "github.com/bxcodec/go-clean-arch/movie/repository/composite"
+	_movieInstrumentRepo "github.com/bxcodec/go-clean-arch/movie/repository/instrument"
 	_movieRepo "github.com/bxcodec/go-clean-arch/movie/repository/movie"
+	_movieMysqlRepo "github.com/bxcodec/go-clean-arch/movie/repository/mysql"
+	_movieRateLimitRepo "github.com/bxcodec/go-clean-arch/movie/repository/ratelimit"
+	_movieResilienceRepo "github.com/bxcodec/go-clean-arch/movie/repository/resilience"
+	_movieTMDbRepo "github.com/bxcodec/go-clean-arch/movie/repository/tmdb"
 	_movieUcase "github.com/bxcodec/go-clean-arch/movie/usecase"
+	_movieInstrumentUcase "github.com/bxcodec/go-clean-arch/movie/usecase/instrument"
+	_quotaRepo "github.com/bxcodec/go-clean-arch/quota/repository/mysql"
+	"github.com/bxcodec/go-clean-arch/tracing"
 )
 
 func init() {
@@ -25,6 +65,13 @@ func init() {
 		panic(err)
 	}
 
+	if viper.GetString(`log.format`) == "json" {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	}
+	if level, err := logrus.ParseLevel(viper.GetString(`log.level`)); err == nil {
+		logrus.SetLevel(level)
+	}
+
 	if viper.GetBool(`debug`) {
 		log.Println("Service RUN on DEBUG mode")
 	}
@@ -52,24 +99,376 @@ func main() {
 		log.Fatal(err)
 	}
 
-	defer func() {
-		err := dbConn.Close()
-		if err != nil {
-			log.Fatal(err)
-		}
-	}()
+	app := lifecycle.NewRegistry()
+	tracer := newTracer()
+	if tracer != nil {
+		otel.SetTracerProvider(tracer)
+		app.Register(lifecycle.Component{
+			Name: "tracer",
+			Stop: tracer.Shutdown,
+		})
+	}
+	app.Register(lifecycle.Component{
+		Name: "mysql",
+		Stop: func(ctx context.Context) error { return dbConn.Close() },
+	})
+
+	reg := prometheus.NewRegistry()
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+	quotaRepo := _quotaRepo.NewMysqlQuotaRepository(dbConn)
 
 	e := echo.New()
-	middL := _movieHttpDeliveryMiddleware.InitMiddleware()
+	middL, err := _movieHttpDeliveryMiddleware.InitMiddleware(_movieHttpDeliveryMiddleware.CORSConfig{
+		AllowOrigins:     viper.GetStringSlice("cors.allow_origins"),
+		AllowMethods:     viper.GetStringSlice("cors.allow_methods"),
+		AllowHeaders:     viper.GetStringSlice("cors.allow_headers"),
+		ExposeHeaders:    viper.GetStringSlice("cors.expose_headers"),
+		MaxAge:           time.Duration(viper.GetInt("cors.max_age")) * time.Second,
+		AllowCredentials: viper.GetBool("cors.allow_credentials"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 	e.Use(middL.CORS)
+	if tracer != nil {
+		e.Use(_movieHttpDeliveryMiddleware.Tracing(tracer))
+	}
+	e.Use(_movieHttpDeliveryMiddleware.RequestLogger(logrus.StandardLogger()))
+	httpMetrics, err := _movieHttpDeliveryMiddleware.Metrics(reg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	e.Use(httpMetrics)
+	var adminOnly []echo.MiddlewareFunc
+	if verifier := newJWTVerifier(); verifier != nil {
+		e.Use(_movieHttpDeliveryMiddleware.JWTAuth(verifier))
+		adminOnly = append(adminOnly, _movieHttpDeliveryMiddleware.RequireRole(viper.GetString("jwt.admin_role")))
+	}
+	var authenticated []echo.MiddlewareFunc
+	if viper.GetBool("auth.enabled") {
+		clientRepo := _clientRepo.NewMysqlClientRepository(dbConn)
+		cu := _clientUcase.NewClientUsecase(clientRepo, quotaRepo, timeoutContext, _clientUcase.Options{
+			PerSecond: viper.GetFloat64("auth.rate_limit.per_second"),
+			Burst:     viper.GetInt("auth.rate_limit.burst"),
+			PerDay:    viper.GetInt64("auth.rate_limit.per_day"),
+			CacheTTL:  time.Duration(viper.GetInt("auth.cache_ttl")) * time.Second,
+		})
+		authenticated = append(authenticated, _movieHttpDeliveryMiddleware.APIKeyAuth(cu))
+	}
+	e.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(reg, promhttp.HandlerOpts{})))
 	logmovieRepo := _logmovieRepo.NewMysqlLogmovieRepository(dbConn)
+	logmovieRepo, err = _logmovieInstrumentRepo.NewInstrumentedLogmovieRepository(logmovieRepo, reg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	omdbClient := newOMDbClient()
+	omdbBaseURL := viper.GetString("omdb.base_url")
+	ar := _movieRepo.NewOMDbMovieRepository(omdbClient, omdbBaseURL, apiKey)
+	ar, breaker, err := withResilience(ar, _movieRepo.ProviderName, "omdb", quotaRepo, reg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	breakerChecks := []domain.HealthChecker{newBreakerChecker(_movieRepo.ProviderName, breaker)}
+	if viper.GetBool("providers.tmdb.enabled") {
+		tmdbClient := &http.Client{Timeout: time.Duration(viper.GetInt("providers.tmdb.timeout")) * time.Second}
+		tmdbRepo := _movieTMDbRepo.NewTMDbMovieRepository(
+			tmdbClient,
+			viper.GetString("providers.tmdb.base_url"),
+			viper.GetString("providers.tmdb.image_base_url"),
+			viper.GetString("providers.tmdb.api_key"),
+		)
+		tmdbRepo, tmdbBreaker, err := withResilience(tmdbRepo, _movieTMDbRepo.ProviderName, "providers.tmdb", quotaRepo, reg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		breakerChecks = append(breakerChecks, newBreakerChecker(_movieTMDbRepo.ProviderName, tmdbBreaker))
+		ar = _movieAggregateRepo.NewAggregateMovieRepository([]domain.MovieProvider{
+			_movieRepo.NewOMDbProvider(ar),
+			_movieTMDbRepo.NewTMDbProvider(tmdbRepo),
+		}, viper.GetStringMapStringSlice("providers.precedence"))
+	}
+	if viper.GetBool("catalog.enabled") {
+		catalogRepo := _movieMysqlRepo.NewMysqlMovieRepository(dbConn, time.Duration(viper.GetInt("catalog.ttl"))*time.Second)
+		ar = _movieCompositeRepo.NewCompositeMovieRepository(catalogRepo, ar,
+			time.Duration(viper.GetInt("catalog.search_timeout_ms"))*time.Millisecond)
+	}
 
-	ar := _movieRepo.NewMysqlMovieRepository(apiKey)
+	criticalCheckers := []domain.HealthChecker{
+		_healthChecker.NewSQLChecker("mysql", dbConn),
+	}
+	optionalCheckers := []domain.HealthChecker{
+		_healthChecker.NewHTTPChecker("omdb", omdbClient, omdbBaseURL),
+	}
+	optionalCheckers = append(optionalCheckers, breakerChecks...)
+	if viper.GetBool("cache.enabled") {
+		getByIDTTL := time.Duration(viper.GetInt("cache.ttl.get_by_id")) * time.Second
+		fetchTTL := time.Duration(viper.GetInt("cache.ttl.fetch")) * time.Second
+		c := newCache()
+		if closer, ok := c.(io.Closer); ok {
+			app.Register(lifecycle.Component{
+				Name: "cache",
+				Stop: func(ctx context.Context) error { return closer.Close() },
+			})
+		}
+		optionalCheckers = append(optionalCheckers, _healthChecker.NewCacheChecker("cache", c))
+		instrumentedCache, err := _instrumentCache.NewInstrumentedCache(c, reg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ar = _movieCacheRepo.NewCachedMovieRepository(ar, instrumentedCache, getByIDTTL, fetchTTL, timeoutContext)
+	}
 
-	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
 	mu := _movieUcase.NewMovieUsecase(ar, timeoutContext)
+	mu, err = _movieInstrumentUcase.NewInstrumentedMovieUsecase(mu, reg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logWriter := _logmovieBatchRepo.NewBatchLogmovieRepository(logmovieRepo, _logmovieBatchRepo.Options{
+		BufferSize:    viper.GetInt("log_writer.buffer_size"),
+		BatchSize:     viper.GetInt("log_writer.batch_size"),
+		FlushInterval: time.Duration(viper.GetInt("log_writer.flush_interval_ms")) * time.Millisecond,
+		FlushTimeout:  time.Duration(viper.GetInt("log_writer.flush_timeout")) * time.Second,
+		Block:         viper.GetBool("log_writer.block"),
+	})
+	if err = _logmovieInstrumentRepo.RegisterBatchWriter(reg, logWriter); err != nil {
+		log.Fatal(err)
+	}
+	app.Register(lifecycle.Component{
+		Name: "log writer",
+		Stop: logWriter.Close,
+	})
+
+	_movieHttpDelivery.NewMovieHandler(e, mu, logWriter, _movieRepo.Normalize, viper.GetBool("catalog.enabled"), authenticated...)
+
+	lu := _logmovieUcase.NewLogmovieUsecase(logmovieRepo, timeoutContext)
+	if len(adminOnly) == 0 {
+		logrus.Warn("jwt is disabled, the movie log is read-only")
+	}
+	_logmovieHttpDelivery.NewLogmovieHandler(e, lu, authenticated, adminOnly...)
+
+	hu := _healthUcase.NewHealthUsecase(criticalCheckers, optionalCheckers,
+		time.Duration(viper.GetInt("health.timeout"))*time.Second,
+		time.Duration(viper.GetInt("health.cache_ttl"))*time.Second,
+	)
+	_healthHttpDelivery.NewHealthHandler(e, hu)
+
+	serverErr := make(chan error, 1)
+	app.Register(lifecycle.Component{
+		Name: "http server",
+		Start: func(ctx context.Context) error {
+			go func() {
+				serverErr <- e.Start(viper.GetString("server.address"))
+			}()
+			return nil
+		},
+		Stop: e.Shutdown,
+	})
+
+	err = run(app, serverErr)
+	if err != nil {
+		log.Println(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("shutdown.timeout"))*time.Second)
+	stopErr := app.Stop(ctx)
+	cancel()
+	if err != nil || stopErr != nil {
+		os.Exit(1)
+	}
+}
+
+// run will start the components and block until a termination signal is received or the http server stops
+func run(app *lifecycle.Registry, serverErr <-chan error) error {
+	err := app.Start(context.Background())
+	if err != nil {
+		return err
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case sig := <-quit:
+		log.Printf("received %s, shutting down", sig)
+		return nil
+	case err = <-serverErr:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	}
+}
+
+// withResilience will wrap the repository of the provider in its instrument, resilience and rate limit decorators.
+// The rate limit wraps the retries so a call is charged once however many attempts it takes. The rate limit
+// is configured under prefix, the retries and the circuit breaker under resilience
+func withResilience(repo domain.MovieRepository, provider, prefix string, quotaRepo domain.QuotaRepository, reg prometheus.Registerer) (domain.MovieRepository, *_movieResilienceRepo.CircuitBreaker, error) {
+	repo, err := _movieInstrumentRepo.NewInstrumentedMovieRepository(repo, reg, provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	breaker := _movieResilienceRepo.NewCircuitBreaker(
+		viper.GetInt("resilience.breaker.failure_threshold"),
+		time.Duration(viper.GetInt("resilience.breaker.open_timeout"))*time.Second,
+	)
+	err = reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: provider + "_breaker_open",
+		Help: "Whether the " + provider + " circuit breaker rejects calls.",
+	}, func() float64 {
+		if breaker.State() == _movieResilienceRepo.StateOpen {
+			return 1
+		}
+		return 0
+	}))
+	if err != nil {
+		return nil, nil, err
+	}
+	repo = _movieResilienceRepo.NewResilientMovieRepository(repo, breaker, _movieResilienceRepo.RetryOptions{
+		MaxAttempts: viper.GetInt("resilience.retry.max_attempts"),
+		BaseDelay:   time.Duration(viper.GetInt("resilience.retry.base_delay_ms")) * time.Millisecond,
+		MaxDelay:    time.Duration(viper.GetInt("resilience.retry.max_delay_ms")) * time.Millisecond,
+	})
+	repo = _movieRateLimitRepo.NewRateLimitedMovieRepository(repo, quotaRepo, _movieRateLimitRepo.Options{
+		Provider:  provider,
+		PerSecond: viper.GetFloat64(prefix + ".rate_limit.per_second"),
+		Burst:     viper.GetInt(prefix + ".rate_limit.burst"),
+		PerDay:    viper.GetInt64(prefix + ".rate_limit.per_day"),
+	})
+	return repo, breaker, nil
+}
+
+// newBreakerChecker will report the provider as unavailable while its circuit breaker rejects calls
+func newBreakerChecker(provider string, breaker *_movieResilienceRepo.CircuitBreaker) domain.HealthChecker {
+	return _healthChecker.NewFuncChecker(provider+"_breaker", func(ctx context.Context) error {
+		if breaker.State() == _movieResilienceRepo.StateOpen {
+			return domain.ErrServiceUnavailable
+		}
+		return nil
+	})
+}
+
+func newOMDbClient() *http.Client {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   time.Duration(viper.GetInt("omdb.dial_timeout")) * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          viper.GetInt("omdb.max_idle_conns"),
+		MaxIdleConnsPerHost:   viper.GetInt("omdb.max_idle_conns_per_host"),
+		IdleConnTimeout:       time.Duration(viper.GetInt("omdb.idle_conn_timeout")) * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if proxy := viper.GetString("omdb.proxy"); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(viper.GetInt("omdb.timeout")) * time.Second,
+	}
+}
+
+// newTracer will create the tracer provider configured under tracing, or nil when tracing is disabled
+func newTracer() *sdktrace.TracerProvider {
+	if !viper.GetBool("tracing.enabled") {
+		return nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name := viper.GetString("tracing.exporter"); name {
+	case "otlp":
+		var endpoint *url.URL
+		endpoint, err = url.Parse(viper.GetString("tracing.otlp.endpoint"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(endpoint.Host),
+			otlptracehttp.WithURLPath(endpoint.Path),
+			otlptracehttp.WithHeaders(viper.GetStringMapString("tracing.otlp.headers")),
+			otlptracehttp.WithTimeout(time.Duration(viper.GetInt("tracing.otlp.timeout")) * time.Second),
+		}
+		if endpoint.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	case "stdout", "":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		log.Fatalf("unknown tracing exporter %q", name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return tracing.NewTracerProvider(exporter, tracing.Options{
+		ServiceName:   viper.GetString("tracing.service_name"),
+		SampleRatio:   viper.GetFloat64("tracing.sample_ratio"),
+		FlushInterval: time.Duration(viper.GetInt("tracing.flush_interval")) * time.Second,
+	})
+}
+
+// newJWTVerifier will build the bearer token verifier from the shared secret, the RSA public key
+// and the JWKS file configured, or return nil when JWT authentication is disabled
+func newJWTVerifier() *jwt.Verifier {
+	if !viper.GetBool("jwt.enabled") {
+		return nil
+	}
+
+	var keys []jwt.Key
+	if secret := viper.GetString("jwt.hs256_secret"); secret != "" {
+		keys = append(keys, jwt.NewHMACKey("", []byte(secret)))
+	}
+	if path := viper.GetString("jwt.rs256_public_key_file"); path != "" {
+		key, err := jwt.LoadRSAPublicKeyPEM("", path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	if path := viper.GetString("jwt.jwks_file"); path != "" {
+		jwks, err := jwt.LoadJWKS(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		keys = append(keys, jwks...)
+	}
+	if len(keys) == 0 {
+		log.Fatal("jwt is enabled but no key is configured")
+	}
 
-	_movieHttpDelivery.NewMovieHandler(e, mu, logmovieRepo)
+	return jwt.NewVerifier(keys, jwt.Options{
+		Issuer:     viper.GetString("jwt.issuer"),
+		Audience:   viper.GetString("jwt.audience"),
+		Leeway:     time.Duration(viper.GetInt("jwt.leeway")) * time.Second,
+		RolesClaim: viper.GetString("jwt.roles_claim"),
+	})
+}
 
-	log.Fatal(e.Start(viper.GetString("server.address")))
+func newCache() domain.Cache {
+	switch driver := viper.GetString("cache.driver"); driver {
+	case "redis":
+		return _redisCache.NewRedisCache(_redisCache.Options{
+			Address:  viper.GetString("cache.redis.address"),
+			Password: viper.GetString("cache.redis.password"),
+			DB:       viper.GetInt("cache.redis.db"),
+			PoolSize: viper.GetInt("cache.redis.pool_size"),
+			Timeout:  time.Duration(viper.GetInt("cache.redis.timeout")) * time.Second,
+		})
+	case "memory", "":
+		return _memoryCache.NewMemoryCache(viper.GetInt("cache.size"))
+	default:
+		log.Fatalf("unknown cache driver %q", driver)
+		return nil
+	}
 }
@@ -0,0 +1,94 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/metrics"
+)
+
+type instrumentedCache struct {
+	cache    domain.Cache
+	requests *prometheus.CounterVec
+	hits     prometheus.Counter
+	misses   prometheus.Counter
+}
+
+// NewInstrumentedCache will count the hits, misses and errors of the given domain.Cache
+// and expose the hit ratio of its lookups
+func NewInstrumentedCache(c domain.Cache, reg prometheus.Registerer) (domain.Cache, error) {
+	requests, err := metrics.NewCounterVec(reg, "cache_requests_total",
+		"Cache operations by operation and result.", "operation", "result")
+	if err != nil {
+		return nil, err
+	}
+	i := &instrumentedCache{
+		cache:    c,
+		requests: requests,
+		hits:     requests.WithLabelValues("get", "hit"),
+		misses:   requests.WithLabelValues("get", "miss"),
+	}
+	err = reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "cache_hit_ratio",
+		Help: "Ratio of cache lookups answered from the cache.",
+	}, i.hitRatio))
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func (i *instrumentedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := i.cache.Get(ctx, key)
+	switch {
+	case err == nil:
+		i.hits.Inc()
+	case errors.Is(err, domain.ErrCacheMiss):
+		i.misses.Inc()
+	default:
+		i.requests.WithLabelValues("get", "error").Inc()
+	}
+
+	return value, err
+}
+
+func (i *instrumentedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := i.cache.Set(ctx, key, value, ttl)
+	i.requests.WithLabelValues("set", result(err)).Inc()
+	return err
+}
+
+func (i *instrumentedCache) Delete(ctx context.Context, key string) error {
+	err := i.cache.Delete(ctx, key)
+	i.requests.WithLabelValues("delete", result(err)).Inc()
+	return err
+}
+
+func (i *instrumentedCache) hitRatio() float64 {
+	hits, misses := counterValue(i.hits), counterValue(i.misses)
+	if hits+misses == 0 {
+		return 0
+	}
+	return hits / (hits + misses)
+}
+
+func counterValue(c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,43 @@
+package instrument_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/cache/instrument"
+	_memoryCache "github.com/bxcodec/go-clean-arch/cache/memory"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestInstrumentedCache(t *testing.T) {
+	ctx := context.TODO()
+	reg := prometheus.NewRegistry()
+	c, err := instrument.NewInstrumentedCache(_memoryCache.NewMemoryCache(10), reg)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Set(ctx, "movie:tt0111161", []byte("{}"), time.Minute))
+	_, err = c.Get(ctx, "movie:tt0111161")
+	assert.NoError(t, err)
+	_, err = c.Get(ctx, "movie:tt0111161")
+	assert.NoError(t, err)
+	_, err = c.Get(ctx, "movie:tt0372784")
+	assert.Equal(t, domain.ErrCacheMiss, err)
+	_, err = c.Get(ctx, "movie:tt0468569")
+	assert.Equal(t, domain.ErrCacheMiss, err)
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	buf := rec.Body
+	assert.Contains(t, buf.String(), `cache_requests_total{operation="get",result="hit"} 2`)
+	assert.Contains(t, buf.String(), `cache_requests_total{operation="get",result="miss"} 2`)
+	assert.Contains(t, buf.String(), `cache_requests_total{operation="set",result="ok"} 1`)
+	assert.Contains(t, buf.String(), "cache_hit_ratio 0.5\n")
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key       string
+	value     []byte
+	expiredAt time.Time
+}
+
+// lru is a size bounded, concurrency safe least-recently-used store with per entry expiration
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+func newLRU(capacity int) *lru {
+	return &lru{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (l *lru) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if l.now().After(e.expiredAt) {
+		l.removeElement(el)
+		return nil, false
+	}
+
+	l.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (l *lru) set(key string, value []byte, ttl time.Duration) {
+	if l.capacity <= 0 || ttl <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiredAt := l.now().Add(ttl)
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiredAt = expiredAt
+		l.ll.MoveToFront(el)
+		return
+	}
+
+	el := l.ll.PushFront(&entry{key: key, value: value, expiredAt: expiredAt})
+	l.items[key] = el
+	for l.ll.Len() > l.capacity {
+		l.removeElement(l.ll.Back())
+	}
+}
+
+func (l *lru) delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.items[key]; ok {
+		l.removeElement(el)
+	}
+}
+
+func (l *lru) removeElement(el *list.Element) {
+	l.ll.Remove(el)
+	delete(l.items, el.Value.(*entry).key)
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type memoryCache struct {
+	store *lru
+}
+
+// NewMemoryCache will create a process local domain.Cache holding at most size entries
+func NewMemoryCache(size int) domain.Cache {
+	return &memoryCache{
+		store: newLRU(size),
+	}
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := m.store.get(key)
+	if !ok {
+		return nil, domain.ErrCacheMiss
+	}
+
+	return value, nil
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.store.set(key, value, ttl)
+	return nil
+}
+
+func (m *memoryCache) Delete(ctx context.Context, key string) error {
+	m.store.delete(key)
+	return nil
+}
@@ -0,0 +1,57 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/cache/memory"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("set-get-delete", func(t *testing.T) {
+		c := memory.NewMemoryCache(10)
+		err := c.Set(ctx, "key", []byte("value"), time.Minute)
+		assert.NoError(t, err)
+
+		value, err := c.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+
+		err = c.Delete(ctx, "key")
+		assert.NoError(t, err)
+		_, err = c.Get(ctx, "key")
+		assert.Equal(t, domain.ErrCacheMiss, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c := memory.NewMemoryCache(10)
+		err := c.Set(ctx, "key", []byte("value"), time.Millisecond)
+		assert.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+		_, err = c.Get(ctx, "key")
+		assert.Equal(t, domain.ErrCacheMiss, err)
+	})
+
+	t.Run("least-recently-used-evicted", func(t *testing.T) {
+		c := memory.NewMemoryCache(2)
+		assert.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+		assert.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+		_, err := c.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.NoError(t, c.Set(ctx, "c", []byte("3"), time.Minute))
+
+		_, err = c.Get(ctx, "b")
+		assert.Equal(t, domain.ErrCacheMiss, err)
+		_, err = c.Get(ctx, "a")
+		assert.NoError(t, err)
+		_, err = c.Get(ctx, "c")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Options represent the connection settings of the redis cache
+type Options struct {
+	Address  string
+	Password string
+	DB       int
+	PoolSize int
+	Timeout  time.Duration
+}
+
+type redisCache struct {
+	client *goredis.Client
+}
+
+// NewRedisCache will create a domain.Cache backed by a redis server
+func NewRedisCache(opts Options) domain.Cache {
+	return &redisCache{
+		client: goredis.NewClient(&goredis.Options{
+			Addr:         opts.Address,
+			Password:     opts.Password,
+			DB:           opts.DB,
+			PoolSize:     opts.PoolSize,
+			DialTimeout:  opts.Timeout,
+			ReadTimeout:  opts.Timeout,
+			WriteTimeout: opts.Timeout,
+			// the callers don't retry a cache, a failing call falls through to the repository instead
+			MaxRetries: -1,
+		}),
+	}
+}
+
+func (rc *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := rc.client.Get(ctx, key).Bytes()
+	if err == goredis.Nil {
+		return nil, domain.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (rc *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return rc.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (rc *redisCache) Delete(ctx context.Context, key string) error {
+	return rc.client.Del(ctx, key).Err()
+}
+
+// Ping will check the server answers, it is used by the health checks
+func (rc *redisCache) Ping(ctx context.Context) error {
+	return rc.client.Ping(ctx).Err()
+}
+
+// Close will close the connection pool of the client
+func (rc *redisCache) Close() error {
+	return rc.client.Close()
+}
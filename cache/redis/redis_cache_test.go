@@ -0,0 +1,86 @@
+package redis_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/cache/redis"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestRedisCache(t *testing.T) {
+	ctx := context.TODO()
+	srv, err := miniredis.Run()
+	require.NoError(t, err)
+	defer srv.Close()
+	srv.RequireAuth("secret")
+
+	c := redis.NewRedisCache(redis.Options{
+		Address:  srv.Addr(),
+		Password: "secret",
+		DB:       1,
+		Timeout:  time.Second,
+	})
+
+	t.Run("set-get-delete", func(t *testing.T) {
+		err := c.Set(ctx, "movie:tt0111161", []byte(`{"imdbID":"tt0111161"}`), time.Minute)
+		require.NoError(t, err)
+
+		value, err := c.Get(ctx, "movie:tt0111161")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"imdbID":"tt0111161"}`, string(value))
+
+		err = c.Delete(ctx, "movie:tt0111161")
+		assert.NoError(t, err)
+		_, err = c.Get(ctx, "movie:tt0111161")
+		assert.Equal(t, domain.ErrCacheMiss, err)
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		p, ok := c.(interface{ Ping(context.Context) error })
+		require.True(t, ok)
+		assert.NoError(t, p.Ping(ctx))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		err := c.Set(ctx, "short", []byte("value"), 10*time.Millisecond)
+		require.NoError(t, err)
+
+		srv.FastForward(20 * time.Millisecond)
+		_, err = c.Get(ctx, "short")
+		assert.Equal(t, domain.ErrCacheMiss, err)
+	})
+
+	t.Run("wrong-password", func(t *testing.T) {
+		bad := redis.NewRedisCache(redis.Options{Address: srv.Addr(), Password: "wrong", Timeout: time.Second})
+		_, err := bad.Get(ctx, "key")
+		assert.Error(t, err)
+		assert.NotEqual(t, domain.ErrCacheMiss, err)
+	})
+
+	t.Run("server-down", func(t *testing.T) {
+		down, err := miniredis.Run()
+		require.NoError(t, err)
+		addr := down.Addr()
+		down.Close()
+
+		bad := redis.NewRedisCache(redis.Options{Address: addr, Timeout: time.Second})
+		_, err = bad.Get(ctx, "key")
+		assert.Error(t, err)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		closing := redis.NewRedisCache(redis.Options{Address: srv.Addr(), Password: "secret", Timeout: time.Second})
+		require.NoError(t, closing.Set(ctx, "key", []byte("value"), time.Minute))
+
+		closer, ok := closing.(io.Closer)
+		require.True(t, ok)
+		assert.NoError(t, closer.Close())
+	})
+}
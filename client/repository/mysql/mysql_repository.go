@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+type mysqlClientRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlClientRepository will create an implementation of domain.ClientRepository
+func NewMysqlClientRepository(db *sql.DB) domain.ClientRepository {
+	return &mysqlClientRepo{
+		DB: db,
+	}
+}
+
+// GetByKeyHash will look the client up by the SHA-256 of its API key, the key itself is never stored
+func (mc *mysqlClientRepo) GetByKeyHash(ctx context.Context, keyHash string) (res domain.Client, err error) {
+	query := `SELECT id, name, key_hash, per_second, burst, daily_quota, active, created_at FROM api_clients WHERE key_hash = ?`
+	ctx, span := tracing.Start(ctx, "mysqlClientRepo.GetByKeyHash", trace.WithSpanKind(trace.SpanKindClient))
+	defer tracing.Finish(span, &err)
+	span.SetAttributes(
+		attribute.String("db.system", "mysql"),
+		attribute.String("db.statement", query),
+	)
+
+	err = mc.DB.QueryRowContext(ctx, query, keyHash).Scan(
+		&res.ID,
+		&res.Name,
+		&res.KeyHash,
+		&res.PerSecond,
+		&res.Burst,
+		&res.DailyQuota,
+		&res.Active,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.Client{}, domain.ErrNotFound
+	}
+
+	return
+}
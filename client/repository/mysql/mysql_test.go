@@ -0,0 +1,53 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	repository "github.com/bxcodec/go-clean-arch/client/repository/mysql"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const keyHash = "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"
+
+var columns = []string{"id", "name", "key_hash", "per_second", "burst", "daily_quota", "active", "created_at"}
+
+func TestGetByKeyHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, name, key_hash, per_second, burst, daily_quota, active, created_at FROM api_clients WHERE key_hash = \\?"
+	rows := sqlmock.NewRows(columns).AddRow(7, "frontend", keyHash, 2.5, 5, 1000, true, time.Now())
+	mock.ExpectQuery(query).WithArgs(keyHash).WillReturnRows(rows)
+
+	c := repository.NewMysqlClientRepository(db)
+
+	client, err := c.GetByKeyHash(context.TODO(), keyHash)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), client.ID)
+	assert.Equal(t, "frontend", client.Name)
+	assert.Equal(t, 2.5, client.PerSecond)
+	assert.Equal(t, int64(1000), client.DailyQuota)
+	assert.True(t, client.Active)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByKeyHashNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM api_clients").WithArgs(keyHash).WillReturnRows(sqlmock.NewRows(columns))
+
+	c := repository.NewMysqlClientRepository(db)
+
+	_, err = c.GetByKeyHash(context.TODO(), keyHash)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/ratelimit"
+)
+
+// Options represent the default request budgets of the clients, used when a client has none of its own,
+// and how long an authenticated key is remembered. A zero budget is unlimited
+type Options struct {
+	PerSecond float64
+	Burst     int
+	PerDay    int64
+	CacheTTL  time.Duration
+}
+
+type cachedClient struct {
+	client    domain.Client
+	expiresAt time.Time
+}
+
+type clientBucket struct {
+	perSecond float64
+	burst     int
+	bucket    *ratelimit.TokenBucket
+}
+
+type clientUsecase struct {
+	clientRepo     domain.ClientRepository
+	daily          *ratelimit.DailyCounter
+	opts           Options
+	contextTimeout time.Duration
+	now            func() time.Time
+
+	mu      sync.Mutex
+	cache   map[string]cachedClient
+	buckets map[string]clientBucket
+}
+
+// NewClientUsecase will create new a clientUsecase object representation of domain.ClientUsecase interface.
+// The daily usage of each client is persisted through quotaRepo
+func NewClientUsecase(c domain.ClientRepository, quotaRepo domain.QuotaRepository, timeout time.Duration, opts Options) domain.ClientUsecase {
+	return &clientUsecase{
+		clientRepo:     c,
+		daily:          ratelimit.NewDailyCounter(quotaRepo),
+		opts:           opts,
+		contextTimeout: timeout,
+		now:            time.Now,
+		cache:          make(map[string]cachedClient),
+		buckets:        make(map[string]clientBucket),
+	}
+}
+
+// Authenticate will return the client owning the API key, ErrUnauthorized for a missing or unknown key and
+// ErrForbidden for a disabled client
+func (u *clientUsecase) Authenticate(c context.Context, apiKey string) (res domain.Client, err error) {
+	if apiKey == "" {
+		return domain.Client{}, domain.ErrUnauthorized
+	}
+
+	sum := sha256.Sum256([]byte(apiKey))
+	keyHash := hex.EncodeToString(sum[:])
+
+	res, ok := u.cached(keyHash)
+	if !ok {
+		ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+		defer cancel()
+
+		res, err = u.clientRepo.GetByKeyHash(ctx, keyHash)
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Client{}, domain.ErrUnauthorized
+		}
+		if err != nil {
+			return domain.Client{}, err
+		}
+		u.store(keyHash, res)
+	}
+
+	if !res.Active {
+		return domain.Client{}, domain.ErrForbidden
+	}
+
+	return res, nil
+}
+
+// AuthenticateToken will return the client of a verified bearer token, known by its subject and held to the
+// default budgets, ErrUnauthorized for a token without subject
+func (u *clientUsecase) AuthenticateToken(c context.Context, subject string) (domain.Client, error) {
+	if subject == "" {
+		return domain.Client{}, domain.ErrUnauthorized
+	}
+
+	return domain.Client{Subject: subject, Name: subject, Active: true}, nil
+}
+
+// Allow will consume one request from the budgets of the client or return a *domain.RateLimitError
+func (u *clientUsecase) Allow(ctx context.Context, client domain.Client) error {
+	if bucket := u.bucket(client); bucket != nil {
+		if ok, retryAfter := bucket.Take(); !ok {
+			return &domain.RateLimitError{RetryAfter: retryAfter}
+		}
+	}
+
+	perDay := client.DailyQuota
+	if perDay <= 0 {
+		perDay = u.opts.PerDay
+	}
+	if perDay <= 0 {
+		return nil
+	}
+
+	if used, resetIn := u.daily.Increment(ctx, budgetKey(client)); used > perDay {
+		return &domain.RateLimitError{RetryAfter: resetIn}
+	}
+
+	return nil
+}
+
+func (u *clientUsecase) cached(keyHash string) (domain.Client, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	entry, ok := u.cache[keyHash]
+	if !ok || !u.now().Before(entry.expiresAt) {
+		return domain.Client{}, false
+	}
+
+	return entry.client, true
+}
+
+func (u *clientUsecase) store(keyHash string, client domain.Client) {
+	if u.opts.CacheTTL <= 0 {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.cache[keyHash] = cachedClient{client: client, expiresAt: u.now().Add(u.opts.CacheTTL)}
+}
+
+// bucket return the token bucket of the client, built again when its budget changed, or nil when unlimited
+func (u *clientUsecase) bucket(client domain.Client) *ratelimit.TokenBucket {
+	perSecond, burst := client.PerSecond, client.Burst
+	if perSecond <= 0 {
+		perSecond, burst = u.opts.PerSecond, u.opts.Burst
+	}
+	if perSecond <= 0 {
+		return nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	key := budgetKey(client)
+	b, ok := u.buckets[key]
+	if !ok || b.perSecond != perSecond || b.burst != burst {
+		b = clientBucket{perSecond: perSecond, burst: burst, bucket: ratelimit.NewTokenBucket(perSecond, burst)}
+		u.buckets[key] = b
+	}
+
+	return b.bucket
+}
+
+// budgetKey return the key the budgets of the client are counted under, by subject for the bearer token clients
+func budgetKey(client domain.Client) string {
+	if client.Subject != "" {
+		return "subject:" + client.Subject
+	}
+	return "client:" + strconv.FormatInt(client.ID, 10)
+}
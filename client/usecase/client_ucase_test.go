@@ -0,0 +1,154 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	ucase "github.com/bxcodec/go-clean-arch/client/usecase"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+)
+
+func hashOf(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAuthenticate(t *testing.T) {
+	mockClient := domain.Client{ID: 7, Name: "frontend", Active: true}
+
+	t.Run("success-cached", func(t *testing.T) {
+		mockClientRepo := new(mocks.ClientRepository)
+		mockClientRepo.On("GetByKeyHash", mock.Anything, hashOf("secret")).Return(mockClient, nil).Once()
+		u := ucase.NewClientUsecase(mockClientRepo, nil, time.Second*2, ucase.Options{CacheTTL: time.Minute})
+
+		for i := 0; i < 3; i++ {
+			client, err := u.Authenticate(context.TODO(), "secret")
+			assert.NoError(t, err)
+			assert.Equal(t, mockClient, client)
+		}
+		mockClientRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing-key", func(t *testing.T) {
+		mockClientRepo := new(mocks.ClientRepository)
+		u := ucase.NewClientUsecase(mockClientRepo, nil, time.Second*2, ucase.Options{})
+
+		_, err := u.Authenticate(context.TODO(), "")
+		assert.Equal(t, domain.ErrUnauthorized, err)
+		mockClientRepo.AssertNotCalled(t, "GetByKeyHash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		mockClientRepo := new(mocks.ClientRepository)
+		mockClientRepo.On("GetByKeyHash", mock.Anything, hashOf("guess")).Return(domain.Client{}, domain.ErrNotFound).Twice()
+		u := ucase.NewClientUsecase(mockClientRepo, nil, time.Second*2, ucase.Options{CacheTTL: time.Minute})
+
+		for i := 0; i < 2; i++ {
+			_, err := u.Authenticate(context.TODO(), "guess")
+			assert.Equal(t, domain.ErrUnauthorized, err)
+		}
+		mockClientRepo.AssertExpectations(t)
+	})
+
+	t.Run("disabled-client", func(t *testing.T) {
+		mockClientRepo := new(mocks.ClientRepository)
+		mockClientRepo.On("GetByKeyHash", mock.Anything, hashOf("revoked")).Return(domain.Client{ID: 8}, nil).Once()
+		u := ucase.NewClientUsecase(mockClientRepo, nil, time.Second*2, ucase.Options{})
+
+		_, err := u.Authenticate(context.TODO(), "revoked")
+		assert.Equal(t, domain.ErrForbidden, err)
+		mockClientRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockClientRepo := new(mocks.ClientRepository)
+		mockClientRepo.On("GetByKeyHash", mock.Anything, hashOf("secret")).Return(domain.Client{}, errors.New("Unexpected Error")).Once()
+		u := ucase.NewClientUsecase(mockClientRepo, nil, time.Second*2, ucase.Options{})
+
+		_, err := u.Authenticate(context.TODO(), "secret")
+		assert.EqualError(t, err, "Unexpected Error")
+		mockClientRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	u := ucase.NewClientUsecase(new(mocks.ClientRepository), nil, time.Second*2, ucase.Options{})
+
+	client, err := u.AuthenticateToken(context.TODO(), "jane")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Client{Subject: "jane", Name: "jane", Active: true}, client)
+
+	_, err = u.AuthenticateToken(context.TODO(), "")
+	assert.Equal(t, domain.ErrUnauthorized, err)
+}
+
+func TestAllow(t *testing.T) {
+	t.Run("per-second", func(t *testing.T) {
+		u := ucase.NewClientUsecase(new(mocks.ClientRepository), nil, time.Second*2, ucase.Options{PerSecond: 100, Burst: 100})
+		client := domain.Client{ID: 1, PerSecond: 1, Burst: 2, Active: true}
+
+		assert.NoError(t, u.Allow(context.TODO(), client))
+		assert.NoError(t, u.Allow(context.TODO(), client))
+
+		err := u.Allow(context.TODO(), client)
+		var rateLimitErr *domain.RateLimitError
+		require.True(t, errors.As(err, &rateLimitErr))
+		assert.True(t, errors.Is(err, domain.ErrRateLimited))
+		assert.True(t, rateLimitErr.RetryAfter > 0)
+
+		// the budget of a client is its own
+		assert.NoError(t, u.Allow(context.TODO(), domain.Client{ID: 2, Active: true}))
+	})
+
+	t.Run("daily-quota", func(t *testing.T) {
+		mockQuotaRepo := new(mocks.QuotaRepository)
+		mockQuotaRepo.On("Increment", mock.Anything, "client:3", mock.Anything).Return(int64(10), nil).Once()
+		mockQuotaRepo.On("Increment", mock.Anything, "client:3", mock.Anything).Return(int64(11), nil).Once()
+		u := ucase.NewClientUsecase(new(mocks.ClientRepository), mockQuotaRepo, time.Second*2, ucase.Options{PerDay: 100})
+		client := domain.Client{ID: 3, DailyQuota: 10, Active: true}
+
+		assert.NoError(t, u.Allow(context.TODO(), client))
+
+		err := u.Allow(context.TODO(), client)
+		var rateLimitErr *domain.RateLimitError
+		require.True(t, errors.As(err, &rateLimitErr))
+		assert.True(t, rateLimitErr.RetryAfter > 0 && rateLimitErr.RetryAfter <= 24*time.Hour)
+		mockQuotaRepo.AssertExpectations(t)
+	})
+
+	t.Run("default-daily-quota", func(t *testing.T) {
+		u := ucase.NewClientUsecase(new(mocks.ClientRepository), nil, time.Second*2, ucase.Options{PerDay: 1})
+		client := domain.Client{ID: 4, Active: true}
+
+		assert.NoError(t, u.Allow(context.TODO(), client))
+		assert.True(t, errors.Is(u.Allow(context.TODO(), client), domain.ErrRateLimited))
+	})
+
+	t.Run("token-daily-quota", func(t *testing.T) {
+		mockQuotaRepo := new(mocks.QuotaRepository)
+		mockQuotaRepo.On("Increment", mock.Anything, "subject:jane", mock.Anything).Return(int64(2), nil).Once()
+		u := ucase.NewClientUsecase(new(mocks.ClientRepository), mockQuotaRepo, time.Second*2, ucase.Options{PerDay: 1})
+
+		err := u.Allow(context.TODO(), domain.Client{Subject: "jane", Active: true})
+		assert.True(t, errors.Is(err, domain.ErrRateLimited))
+		mockQuotaRepo.AssertExpectations(t)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		u := ucase.NewClientUsecase(new(mocks.ClientRepository), nil, time.Second*2, ucase.Options{})
+		client := domain.Client{ID: 5, Active: true}
+
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, u.Allow(context.TODO(), client))
+		}
+	})
+}
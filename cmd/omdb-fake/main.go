@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/omdbfake"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	fixtures := flag.String("fixtures", "", "JSON file holding an array of OMDb movies, the built-in fixtures when empty")
+	apiKey := flag.String("apikey", "", "the only API key accepted, any key when empty")
+	latency := flag.Duration("latency", 0, "delay added to every answer, e.g. 200ms")
+	requestLimit := flag.Int("request-limit", 0, "requests served before answering \"Request limit reached!\", 0 for no limit")
+	failEvery := flag.Int("fail-every", 0, "answer 503 to every n-th request, 0 for never")
+	flag.Parse()
+
+	opts := omdbfake.Options{
+		APIKey:       *apiKey,
+		Latency:      *latency,
+		RequestLimit: *requestLimit,
+		FailEvery:    *failEvery,
+	}
+	if *fixtures != "" {
+		movies, err := omdbfake.LoadMovies(*fixtures)
+		if err != nil {
+			logrus.Fatalf("load fixtures %s: %s", *fixtures, err)
+		}
+		opts.Movies = movies
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           omdbfake.New(opts),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	logrus.Infof("omdb fake listening on %s", *addr)
+	logrus.Fatal(srv.ListenAndServe())
+}
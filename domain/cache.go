@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Cache represent the key-value cache contract implemented by each cache backend
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
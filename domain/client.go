@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Client represent a consumer of the API, authenticated by its API key. Only the SHA-256 of the key is stored.
+// A client authenticated by a bearer token has no ID but the Subject of the token instead.
+// A zero budget falls back to the configured default
+type Client struct {
+	ID         int64     `json:"id"`
+	Subject    string    `json:"subject,omitempty"`
+	Name       string    `json:"name"`
+	KeyHash    string    `json:"-"`
+	PerSecond  float64   `json:"per_second"`
+	Burst      int       `json:"burst"`
+	DailyQuota int64     `json:"daily_quota"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ClientRepository represent the client's repository contract
+type ClientRepository interface {
+	GetByKeyHash(ctx context.Context, keyHash string) (Client, error)
+}
+
+// ClientUsecase represent the authentication and the request budgets of the API clients
+type ClientUsecase interface {
+	Authenticate(ctx context.Context, apiKey string) (Client, error)
+	AuthenticateToken(ctx context.Context, subject string) (Client, error)
+	Allow(ctx context.Context, client Client) error
+}
+
+type clientKey struct{}
+
+// NewClientContext will return a copy of ctx carrying the authenticated client
+func NewClientContext(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext return the authenticated client carried by ctx, if any
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
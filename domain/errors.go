@@ -11,4 +11,40 @@ var (
 	ErrConflict = errors.New("Your Item already exist")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrBadParamInput = errors.New("Given Param is not valid")
+	// ErrInvalidAPIKey will throw if the movie provider rejects the configured API key
+	ErrInvalidAPIKey = errors.New("Movie provider rejected the API key")
+	// ErrRateLimited will throw if the movie provider request limit is reached
+	ErrRateLimited = errors.New("Request limit reached")
+	// ErrTooManyResults will throw if the given search is too broad to be answered
+	ErrTooManyResults = errors.New("Too many results, please narrow down the search")
+	// ErrServiceUnavailable will throw if the movie provider is considered down and calls fail fast
+	ErrServiceUnavailable = errors.New("Movie provider is unavailable, please try again later")
+	// ErrUnauthorized will throw if the request carries no API key or an unknown one
+	ErrUnauthorized = errors.New("Missing or invalid API key")
+	// ErrInvalidToken will throw if the route requires a bearer token and the request carries none or an invalid one
+	ErrInvalidToken = errors.New("Missing or invalid bearer token")
+	// ErrForbidden will throw if the authenticated client is not allowed to access the resource
+	ErrForbidden = errors.New("You are not allowed to access this resource")
+	// ErrCacheMiss will throw if the requested key is not exists in the cache
+	ErrCacheMiss = errors.New("Cache miss")
 )
+
+// FieldError represent why a single request field is not valid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when request params are not valid, it unwraps to ErrBadParamInput
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return ErrBadParamInput.Error()
+}
+
+// Unwrap return ErrBadParamInput so the error can be matched with errors.Is
+func (e *ValidationError) Unwrap() error {
+	return ErrBadParamInput
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// HealthStatus represent the status of a dependency or of the whole service
+type HealthStatus string
+
+const (
+	// HealthUp means the dependency answered the probe
+	HealthUp HealthStatus = "up"
+	// HealthDown means the dependency failed the probe
+	HealthDown HealthStatus = "down"
+	// HealthDegraded means the service is running but some optional dependency is down
+	HealthDegraded HealthStatus = "degraded"
+)
+
+// HealthCheck represent the result of probing a single dependency
+type HealthCheck struct {
+	Status    HealthStatus `json:"status"`
+	LatencyMs float64      `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+// HealthReport represent the status of the service along with each dependency probed
+type HealthReport struct {
+	Status HealthStatus           `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// HealthChecker represent a probe of a single dependency
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// HealthUsecase represent the health's usecases
+type HealthUsecase interface {
+	Liveness(ctx context.Context) HealthReport
+	Readiness(ctx context.Context) HealthReport
+}
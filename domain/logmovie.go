@@ -1,8 +1,38 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// Logmovie represent a movie that has been looked up through the API
+type Logmovie struct {
+	ID           int64     `json:"id"`
+	Title        string    `json:"title" validate:"required"`
+	ImdbID       string    `json:"imdbID"`
+	Year         string    `json:"year"`
+	Released     string    `json:"released"`
+	ImdbRating   string    `json:"imdbRating"`
+	ViewCount    int64     `json:"view_count"`
+	LastViewedAt time.Time `json:"last_viewed_at"`
+}
+
+// LogmovieUsecase represent the logmovie's usecases
+type LogmovieUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64) ([]Logmovie, string, error)
+	GetByID(ctx context.Context, id int64) (Logmovie, error)
+	MostViewed(ctx context.Context, num int64) ([]Logmovie, error)
+	Update(ctx context.Context, l *Logmovie) error
+	Delete(ctx context.Context, id int64) error
+}
 
 // LogmovieRepository represent the logmovie's repository contract
 type LogmovieRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64) (res []Logmovie, nextCursor string, err error)
+	GetByID(ctx context.Context, id int64) (Logmovie, error)
+	MostViewed(ctx context.Context, num int64) ([]Logmovie, error)
 	Store(ctx context.Context, m *Movies) error
+	StoreBatch(ctx context.Context, ms []Movies) error
+	Update(ctx context.Context, l *Logmovie) error
+	Delete(ctx context.Context, id int64) error
 }
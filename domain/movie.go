@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type SearchResult struct {
@@ -9,7 +10,18 @@ type SearchResult struct {
 	TotalResults string
 }
 
-// Movies ...
+// MoviePage represent a single page of movies along with the cursors around it
+type MoviePage struct {
+	Movies     []Movies `json:"movies"`
+	Total      int      `json:"total"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	PrevCursor string   `json:"prev_cursor,omitempty"`
+	// Fallback is set on a page answered by the catalog in place of an unavailable remote search
+	Fallback bool `json:"-"`
+}
+
+// Movies represent a movie the way OMDb returns it, every field kept as the raw string. It is the
+// shape served by the legacy /movies routes, see Movie for the normalized one
 type Movies struct {
 	ID         string   `json:"imdbID"`
 	Title      string   `json:"title,omitempty"`
@@ -23,12 +35,105 @@ type Movies struct {
 	Actors     string   `json:"Actors,omitempty"`
 	Country    string   `json:"Country,omitempty"`
 	Awards     string   `json:"Awards,omitempty"`
+	Plot       string   `json:"Plot,omitempty"`
 	Poster     string   `json:"Poster,omitempty"`
+	Backdrop   string   `json:"Backdrop,omitempty"`
+	Keywords   string   `json:"Keywords,omitempty"`
 	Ratings    []Rating `json:"Ratings,omitempty"`
 	Type       string   `json:"Type,omitempty"`
+	Metascore  string   `json:"Metascore,omitempty"`
 	ImdbRating string   `json:"imdbRating,omitempty"`
+	ImdbVotes  string   `json:"imdbVotes,omitempty"`
 	DVD        string   `json:"updated_at,omitempty"`
 	Released   string   `json:"created_at,omitempty"`
+	// Sources maps the fields of a movie merged from several providers to the provider that supplied them
+	Sources map[string]string `json:"Sources,omitempty"`
+	// Partial is set on a merged movie some provider failed to answer, its fields may be missing until retried
+	Partial bool `json:"-"`
+}
+
+// Movie represent the normalized movie served by the /v2 routes, a value OMDb reports as "N/A" is left zero
+type Movie struct {
+	ImdbID string
+	Title  string
+	Type   MovieType
+	Year   int
+	// EndYear is the last year of a series that ended
+	EndYear    int
+	Rated      string
+	Released   *time.Time
+	DVD        *time.Time
+	Runtime    time.Duration
+	Genres     []string
+	Directors  []string
+	Writers    []string
+	Actors     []string
+	Languages  []string
+	Countries  []string
+	Plot       string
+	Awards     string
+	Poster     string
+	Backdrop   string
+	Keywords   []string
+	Ratings    []MovieRating
+	Metascore  int
+	ImdbRating float64
+	ImdbVotes  int
+	Sources    map[string]string
+}
+
+// MovieNormalizer will parse the raw strings of a movie into the normalized movie
+type MovieNormalizer func(m Movies) Movie
+
+// MovieRating represent the rating of a movie by a single source, Score being the rating on a 0 to 100 scale
+type MovieRating struct {
+	Source string  `json:"source"`
+	Value  string  `json:"value"`
+	Score  float64 `json:"score"`
+}
+
+// MovieType represent the kind of title a search is restricted to
+type MovieType string
+
+const (
+	// MovieTypeMovie restricts a search to movies
+	MovieTypeMovie MovieType = "movie"
+	// MovieTypeSeries restricts a search to series
+	MovieTypeSeries MovieType = "series"
+	// MovieTypeEpisode restricts a search to episodes
+	MovieTypeEpisode MovieType = "episode"
+)
+
+// PlotLength represent the length of the plot returned with a movie
+type PlotLength string
+
+const (
+	// PlotShort returns the short plot of the movie
+	PlotShort PlotLength = "short"
+	// PlotFull returns the full plot of the movie
+	PlotFull PlotLength = "full"
+)
+
+// SearchSource represent where a movie search is answered from
+type SearchSource string
+
+const (
+	// SearchSourceAuto searches the remote provider, falling back to the local catalog while it is unavailable
+	SearchSourceAuto SearchSource = "auto"
+	// SearchSourceLocal searches the local catalog only, ranked by relevance
+	SearchSourceLocal SearchSource = "local"
+	// SearchSourceRemote searches the remote provider only
+	SearchSourceRemote SearchSource = "remote"
+)
+
+// SearchCriteria represent the filters of a movie search, a zero Year or an empty Type leaves the filter unset and
+// an empty Source searches like SearchSourceAuto
+type SearchCriteria struct {
+	Searchword string
+	Year       int
+	Type       MovieType
+	Source     SearchSource
+	Cursor     string
 }
 
 type Rating struct {
@@ -38,12 +143,26 @@ type Rating struct {
 
 // MovieUsecase represent the movie's usecases
 type MovieUsecase interface {
-	Fetch(ctx context.Context, cursor string, searchword string) ([]Movies, string, error)
-	GetByID(ctx context.Context, id string) (Movies, error)
+	Fetch(ctx context.Context, criteria SearchCriteria) (MoviePage, error)
+	GetByID(ctx context.Context, id string, plot PlotLength) (Movies, error)
 }
 
 // MovieRepository represent the movie's repository contract
 type MovieRepository interface {
-	Fetch(ctx context.Context, cursor string, searchword string) (res []Movies, nextCursor string, err error)
-	GetByID(ctx context.Context, id string) (Movies, error)
+	Fetch(ctx context.Context, criteria SearchCriteria) (res MoviePage, err error)
+	GetByID(ctx context.Context, id string, plot PlotLength) (Movies, error)
+}
+
+// MovieCatalogRepository represent the local movie catalog, a MovieRepository the movies looked up elsewhere are
+// stored into. GetByID returns ErrNotFound when the movie, or its plot of the requested length, isn't stored yet
+type MovieCatalogRepository interface {
+	MovieRepository
+	Store(ctx context.Context, m *Movies, plot PlotLength) error
+}
+
+// MovieProvider represent a source of movie metadata merged by imdbID with the other providers. GetByID returns
+// ErrNotFound when the provider doesn't know the movie
+type MovieProvider interface {
+	Name() string
+	GetByID(ctx context.Context, imdbID string, plot PlotLength) (Movies, error)
 }
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// dateLayout is the layout dates are served with by the /v2 routes
+const dateLayout = "2006-01-02"
+
+// movieJSON is the wire shape of a Movie, with dates as 2006-01-02 and the runtime in minutes
+type movieJSON struct {
+	ImdbID         string            `json:"imdb_id"`
+	Title          string            `json:"title"`
+	Type           MovieType         `json:"type,omitempty"`
+	Year           int               `json:"year,omitempty"`
+	EndYear        int               `json:"end_year,omitempty"`
+	Rated          string            `json:"rated,omitempty"`
+	Released       string            `json:"released,omitempty"`
+	DVD            string            `json:"dvd,omitempty"`
+	RuntimeMinutes int               `json:"runtime_minutes,omitempty"`
+	Genres         []string          `json:"genres,omitempty"`
+	Directors      []string          `json:"directors,omitempty"`
+	Writers        []string          `json:"writers,omitempty"`
+	Actors         []string          `json:"actors,omitempty"`
+	Languages      []string          `json:"languages,omitempty"`
+	Countries      []string          `json:"countries,omitempty"`
+	Plot           string            `json:"plot,omitempty"`
+	Awards         string            `json:"awards,omitempty"`
+	Poster         string            `json:"poster,omitempty"`
+	Backdrop       string            `json:"backdrop,omitempty"`
+	Keywords       []string          `json:"keywords,omitempty"`
+	Ratings        []MovieRating     `json:"ratings,omitempty"`
+	Metascore      int               `json:"metascore,omitempty"`
+	ImdbRating     float64           `json:"imdb_rating,omitempty"`
+	ImdbVotes      int               `json:"imdb_votes,omitempty"`
+	Sources        map[string]string `json:"sources,omitempty"`
+}
+
+// MarshalJSON will encode the movie in its /v2 shape
+func (m Movie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(movieJSON{
+		ImdbID:         m.ImdbID,
+		Title:          m.Title,
+		Type:           m.Type,
+		Year:           m.Year,
+		EndYear:        m.EndYear,
+		Rated:          m.Rated,
+		Released:       formatDate(m.Released),
+		DVD:            formatDate(m.DVD),
+		RuntimeMinutes: int(m.Runtime / time.Minute),
+		Genres:         m.Genres,
+		Directors:      m.Directors,
+		Writers:        m.Writers,
+		Actors:         m.Actors,
+		Languages:      m.Languages,
+		Countries:      m.Countries,
+		Plot:           m.Plot,
+		Awards:         m.Awards,
+		Poster:         m.Poster,
+		Backdrop:       m.Backdrop,
+		Keywords:       m.Keywords,
+		Ratings:        m.Ratings,
+		Metascore:      m.Metascore,
+		ImdbRating:     m.ImdbRating,
+		ImdbVotes:      m.ImdbVotes,
+		Sources:        m.Sources,
+	})
+}
+
+// UnmarshalJSON will decode a movie from its /v2 shape
+func (m *Movie) UnmarshalJSON(data []byte) error {
+	var raw movieJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = Movie{
+		ImdbID:     raw.ImdbID,
+		Title:      raw.Title,
+		Type:       raw.Type,
+		Year:       raw.Year,
+		EndYear:    raw.EndYear,
+		Rated:      raw.Rated,
+		Released:   unformatDate(raw.Released),
+		DVD:        unformatDate(raw.DVD),
+		Runtime:    time.Duration(raw.RuntimeMinutes) * time.Minute,
+		Genres:     raw.Genres,
+		Directors:  raw.Directors,
+		Writers:    raw.Writers,
+		Actors:     raw.Actors,
+		Languages:  raw.Languages,
+		Countries:  raw.Countries,
+		Plot:       raw.Plot,
+		Awards:     raw.Awards,
+		Poster:     raw.Poster,
+		Backdrop:   raw.Backdrop,
+		Keywords:   raw.Keywords,
+		Ratings:    raw.Ratings,
+		Metascore:  raw.Metascore,
+		ImdbRating: raw.ImdbRating,
+		ImdbVotes:  raw.ImdbVotes,
+		Sources:    raw.Sources,
+	}
+	return nil
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func unformatDate(s string) *time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,32 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+func TestMovieJSON(t *testing.T) {
+	released := time.Date(1994, time.October, 14, 0, 0, 0, 0, time.UTC)
+	m := domain.Movie{
+		ImdbID:   "tt0111161",
+		Title:    "The Shawshank Redemption",
+		Released: &released,
+		Runtime:  142 * time.Minute,
+		Ratings:  []domain.MovieRating{{Source: "Rotten Tomatoes", Value: "91%", Score: 91}},
+	}
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imdb_id":"tt0111161","title":"The Shawshank Redemption","released":"1994-10-14",`+
+		`"runtime_minutes":142,"ratings":[{"source":"Rotten Tomatoes","value":"91%","score":91}]}`, string(data))
+
+	var decoded domain.Movie
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, m, decoded)
+}
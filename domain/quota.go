@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitError is returned when a request budget is exhausted, it unwraps to ErrRateLimited
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return ErrRateLimited.Error()
+}
+
+// Unwrap return ErrRateLimited so the error can be matched with errors.Is
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// QuotaRepository represent the persisted daily usage counter of an external API
+type QuotaRepository interface {
+	Used(ctx context.Context, provider string, day time.Time) (int64, error)
+	Increment(ctx context.Context, provider string, day time.Time) (int64, error)
+}
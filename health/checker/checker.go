@@ -0,0 +1,87 @@
+package checker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// cacheProbeKey is looked up by the cache probe, it is never written so a miss is the expected answer
+const cacheProbeKey = "health:probe"
+
+// pinger is implemented by the caches backed by a server, such as redis
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type funcChecker struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// NewFuncChecker will create a domain.HealthChecker calling the given function
+func NewFuncChecker(name string, check func(ctx context.Context) error) domain.HealthChecker {
+	return &funcChecker{
+		name:  name,
+		check: check,
+	}
+}
+
+func (f *funcChecker) Name() string {
+	return f.name
+}
+
+func (f *funcChecker) Check(ctx context.Context) error {
+	return f.check(ctx)
+}
+
+// NewSQLChecker will create a domain.HealthChecker pinging the given database
+func NewSQLChecker(name string, db *sql.DB) domain.HealthChecker {
+	return NewFuncChecker(name, db.PingContext)
+}
+
+// NewHTTPChecker will create a domain.HealthChecker requesting the given URL.
+// Any answer below 500 means the server is reachable
+func NewHTTPChecker(name string, client *http.Client, url string) domain.HealthChecker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return NewFuncChecker(name, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// NewCacheChecker will create a domain.HealthChecker pinging the server of the given cache, or looking up a
+// probe key when the cache has no server to ping. The probe never writes, so it doesn't evict the cached entries
+func NewCacheChecker(name string, c domain.Cache) domain.HealthChecker {
+	if p, ok := c.(pinger); ok {
+		return NewFuncChecker(name, p.Ping)
+	}
+
+	return NewFuncChecker(name, func(ctx context.Context) error {
+		_, err := c.Get(ctx, cacheProbeKey)
+		if err != nil && !errors.Is(err, domain.ErrCacheMiss) {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,79 @@
+package checker_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	_memoryCache "github.com/bxcodec/go-clean-arch/cache/memory"
+	_redisCache "github.com/bxcodec/go-clean-arch/cache/redis"
+	"github.com/bxcodec/go-clean-arch/health/checker"
+)
+
+func TestSQLChecker(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	c := checker.NewSQLChecker("mysql", db)
+	assert.Equal(t, "mysql", c.Name())
+	assert.NoError(t, c.Check(context.TODO()))
+
+	db.Close()
+	assert.Error(t, c.Check(context.TODO()))
+}
+
+func TestHTTPChecker(t *testing.T) {
+	status := int32(http.StatusUnauthorized)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer ts.Close()
+
+	c := checker.NewHTTPChecker("omdb", ts.Client(), ts.URL)
+	assert.NoError(t, c.Check(context.TODO()))
+
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	assert.EqualError(t, c.Check(context.TODO()), "unexpected status 503")
+
+	ts.Close()
+	assert.Error(t, c.Check(context.TODO()))
+}
+
+func TestCacheChecker(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("memory", func(t *testing.T) {
+		cache := _memoryCache.NewMemoryCache(1)
+		require.NoError(t, cache.Set(ctx, "movie:tt0111161", []byte("value"), time.Minute))
+
+		c := checker.NewCacheChecker("cache", cache)
+		assert.NoError(t, c.Check(ctx))
+
+		// the probe must not evict the only entry of a full cache
+		value, err := cache.Get(ctx, "movie:tt0111161")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", string(value))
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		srv, err := miniredis.Run()
+		require.NoError(t, err)
+		addr := srv.Addr()
+
+		c := checker.NewCacheChecker("cache", _redisCache.NewRedisCache(_redisCache.Options{Address: addr, Timeout: time.Second}))
+		assert.NoError(t, c.Check(ctx))
+
+		srv.Close()
+		assert.Error(t, c.Check(ctx))
+	})
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// HealthHandler  represent the httphandler for the health probes
+type HealthHandler struct {
+	HUsecase domain.HealthUsecase
+}
+
+// NewHealthHandler will initialize the healthz/ and readyz/ resources endpoint
+func NewHealthHandler(e *echo.Echo, us domain.HealthUsecase) {
+	handler := &HealthHandler{
+		HUsecase: us,
+	}
+	e.GET("/healthz", handler.Liveness)
+	e.GET("/readyz", handler.Readiness)
+}
+
+// Liveness will report every dependency, it only fails when the service cannot answer at all
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	report := h.HUsecase.Liveness(ctx)
+	return c.JSON(http.StatusOK, report)
+}
+
+// Readiness will report the critical dependencies, failing when any of them is down
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	report := h.HUsecase.Readiness(ctx)
+	if report.Status != domain.HealthUp {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
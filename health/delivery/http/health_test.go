@@ -0,0 +1,88 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	healthHttp "github.com/bxcodec/go-clean-arch/health/delivery/http"
+)
+
+func TestLiveness(t *testing.T) {
+	mockUCase := new(mocks.HealthUsecase)
+	mockReport := domain.HealthReport{
+		Status: domain.HealthDegraded,
+		Checks: map[string]domain.HealthCheck{
+			"mysql": {Status: domain.HealthUp, LatencyMs: 1.5},
+			"omdb":  {Status: domain.HealthDown, Error: "connection refused"},
+		},
+	}
+	mockUCase.On("Liveness", mock.Anything).Return(mockReport)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/healthz", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := healthHttp.HealthHandler{
+		HUsecase: mockUCase,
+	}
+	err = handler.Liveness(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"degraded"`)
+	assert.Contains(t, rec.Body.String(), `"latency_ms":1.5`)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestReadiness(t *testing.T) {
+	t.Run("up", func(t *testing.T) {
+		mockUCase := new(mocks.HealthUsecase)
+		mockUCase.On("Readiness", mock.Anything).Return(domain.HealthReport{Status: domain.HealthUp})
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/readyz", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := healthHttp.HealthHandler{
+			HUsecase: mockUCase,
+		}
+		err = handler.Readiness(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("down", func(t *testing.T) {
+		mockUCase := new(mocks.HealthUsecase)
+		mockUCase.On("Readiness", mock.Anything).Return(domain.HealthReport{Status: domain.HealthDown})
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/readyz", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := healthHttp.HealthHandler{
+			HUsecase: mockUCase,
+		}
+		err = handler.Readiness(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
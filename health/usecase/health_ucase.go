@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type healthUsecase struct {
+	critical     []domain.HealthChecker
+	optional     []domain.HealthChecker
+	checkTimeout time.Duration
+	cacheTTL     time.Duration
+
+	mu      sync.Mutex
+	results map[string]domain.HealthCheck
+	group   singleflight.Group
+	now     func() time.Time
+}
+
+// NewHealthUsecase will create new an healthUsecase object representation of domain.HealthUsecase interface.
+// The critical checkers decide the readiness of the service, the optional ones are only reported by the liveness.
+// Each probe result is reused for cacheTTL so frequent probes do not hammer the dependencies
+func NewHealthUsecase(critical, optional []domain.HealthChecker, checkTimeout, cacheTTL time.Duration) domain.HealthUsecase {
+	return &healthUsecase{
+		critical:     critical,
+		optional:     optional,
+		checkTimeout: checkTimeout,
+		cacheTTL:     cacheTTL,
+		results:      make(map[string]domain.HealthCheck),
+		now:          time.Now,
+	}
+}
+
+// Liveness will probe every dependency, the service is degraded when any of them is down
+func (h *healthUsecase) Liveness(ctx context.Context) domain.HealthReport {
+	checkers := append(append([]domain.HealthChecker{}, h.critical...), h.optional...)
+	report := h.run(ctx, checkers)
+	if report.Status == domain.HealthDown {
+		report.Status = domain.HealthDegraded
+	}
+
+	return report
+}
+
+// Readiness will probe the critical dependencies, the service is down when any of them is down
+func (h *healthUsecase) Readiness(ctx context.Context) domain.HealthReport {
+	return h.run(ctx, h.critical)
+}
+
+func (h *healthUsecase) run(ctx context.Context, checkers []domain.HealthChecker) domain.HealthReport {
+	report := domain.HealthReport{
+		Status: domain.HealthUp,
+		Checks: make(map[string]domain.HealthCheck, len(checkers)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(checker domain.HealthChecker) {
+			defer wg.Done()
+			res := h.check(ctx, checker)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[checker.Name()] = res
+			if res.Status != domain.HealthUp {
+				report.Status = domain.HealthDown
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (h *healthUsecase) check(ctx context.Context, checker domain.HealthChecker) domain.HealthCheck {
+	name := checker.Name()
+
+	h.mu.Lock()
+	res, ok := h.results[name]
+	h.mu.Unlock()
+	if ok && h.now().Sub(res.CheckedAt) < h.cacheTTL {
+		return res
+	}
+
+	ch := h.group.DoChan(name, func() (interface{}, error) {
+		// the probe is detached from the request so a caller going away does not cache a failure
+		probeCtx, cancel := context.WithTimeout(context.Background(), h.checkTimeout)
+		defer cancel()
+
+		start := h.now()
+		err := checker.Check(probeCtx)
+		res := domain.HealthCheck{
+			Status:    domain.HealthUp,
+			LatencyMs: float64(h.now().Sub(start)) / float64(time.Millisecond),
+			CheckedAt: start,
+		}
+		if err != nil {
+			res.Status = domain.HealthDown
+			res.Error = err.Error()
+		}
+
+		h.mu.Lock()
+		h.results[name] = res
+		h.mu.Unlock()
+		return res, nil
+	})
+
+	select {
+	case r := <-ch:
+		return r.Val.(domain.HealthCheck)
+	case <-ctx.Done():
+		return domain.HealthCheck{
+			Status:    domain.HealthDown,
+			Error:     ctx.Err().Error(),
+			CheckedAt: h.now(),
+		}
+	}
+}
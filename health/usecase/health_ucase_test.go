@@ -0,0 +1,90 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	ucase "github.com/bxcodec/go-clean-arch/health/usecase"
+)
+
+func newMockChecker(name string, err error) *mocks.HealthChecker {
+	checker := new(mocks.HealthChecker)
+	checker.On("Name").Return(name)
+	checker.On("Check", mock.Anything).Return(err)
+	return checker
+}
+
+func TestReadiness(t *testing.T) {
+	t.Run("up", func(t *testing.T) {
+		mysql := newMockChecker("mysql", nil)
+		omdb := newMockChecker("omdb", errors.New("connection refused"))
+		u := ucase.NewHealthUsecase([]domain.HealthChecker{mysql}, []domain.HealthChecker{omdb}, time.Second, time.Minute)
+
+		report := u.Readiness(context.TODO())
+		assert.Equal(t, domain.HealthUp, report.Status)
+		assert.Len(t, report.Checks, 1)
+		assert.Equal(t, domain.HealthUp, report.Checks["mysql"].Status)
+		omdb.AssertNotCalled(t, "Check", mock.Anything)
+	})
+
+	t.Run("down", func(t *testing.T) {
+		mysql := newMockChecker("mysql", errors.New("sql: database is closed"))
+		u := ucase.NewHealthUsecase([]domain.HealthChecker{mysql}, nil, time.Second, time.Minute)
+
+		report := u.Readiness(context.TODO())
+		assert.Equal(t, domain.HealthDown, report.Status)
+		assert.Equal(t, "sql: database is closed", report.Checks["mysql"].Error)
+	})
+}
+
+func TestLiveness(t *testing.T) {
+	mysql := newMockChecker("mysql", nil)
+	omdb := newMockChecker("omdb", errors.New("connection refused"))
+	u := ucase.NewHealthUsecase([]domain.HealthChecker{mysql}, []domain.HealthChecker{omdb}, time.Second, time.Minute)
+
+	report := u.Liveness(context.TODO())
+	assert.Equal(t, domain.HealthDegraded, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, domain.HealthDown, report.Checks["omdb"].Status)
+}
+
+func TestProbeCache(t *testing.T) {
+	t.Run("cached", func(t *testing.T) {
+		mysql := newMockChecker("mysql", nil)
+		u := ucase.NewHealthUsecase([]domain.HealthChecker{mysql}, nil, time.Second, time.Minute)
+
+		u.Readiness(context.TODO())
+		u.Liveness(context.TODO())
+		mysql.AssertNumberOfCalls(t, "Check", 1)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		mysql := newMockChecker("mysql", nil)
+		u := ucase.NewHealthUsecase([]domain.HealthChecker{mysql}, nil, time.Second, 0)
+
+		u.Readiness(context.TODO())
+		u.Readiness(context.TODO())
+		mysql.AssertNumberOfCalls(t, "Check", 2)
+	})
+}
+
+func TestProbeTimeout(t *testing.T) {
+	slow := new(mocks.HealthChecker)
+	slow.On("Name").Return("omdb")
+	slow.On("Check", mock.Anything).Return(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	u := ucase.NewHealthUsecase([]domain.HealthChecker{slow}, nil, 20*time.Millisecond, time.Minute)
+
+	report := u.Readiness(context.TODO())
+	assert.Equal(t, domain.HealthDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["omdb"].Error)
+}
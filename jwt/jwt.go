@@ -0,0 +1,172 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	// ErrMalformed will throw if the token is not a well formed JWS compact serialization
+	ErrMalformed = errors.New("jwt: malformed token")
+	// ErrUnsupportedAlgorithm will throw if the token is signed with an algorithm other than HS256 and RS256
+	ErrUnsupportedAlgorithm = errors.New("jwt: unsupported algorithm")
+	// ErrInvalidSignature will throw if no configured key verifies the signature
+	ErrInvalidSignature = errors.New("jwt: invalid signature")
+	// ErrExpired will throw if the token has no expiry or is expired
+	ErrExpired = errors.New("jwt: token is expired")
+	// ErrNotValidYet will throw if the token is used before its nbf claim
+	ErrNotValidYet = errors.New("jwt: token is not valid yet")
+	// ErrInvalidIssuer will throw if the iss claim is not the expected issuer
+	ErrInvalidIssuer = errors.New("jwt: invalid issuer")
+	// ErrInvalidAudience will throw if the aud claim doesn't contain the expected audience
+	ErrInvalidAudience = errors.New("jwt: invalid audience")
+)
+
+// Claims represent the verified claims of a token
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	Roles     []string
+}
+
+// HasRole reports whether the token grants the role
+func (c Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Options represent the checks applied to the claims, an empty Issuer or Audience is not checked
+type Options struct {
+	Issuer   string
+	Audience string
+	// Leeway tolerates that much clock skew on exp and nbf
+	Leeway time.Duration
+	// RolesClaim is the claim holding the roles, either an array or a space separated string. Defaults to roles
+	RolesClaim string
+}
+
+// Verifier will check the signature and the claims of the tokens
+type Verifier struct {
+	keys []Key
+	opts Options
+	now  func() time.Time
+}
+
+// NewVerifier will create a Verifier accepting tokens signed by one of the keys
+func NewVerifier(keys []Key, opts Options) *Verifier {
+	if opts.RolesClaim == "" {
+		opts.RolesClaim = "roles"
+	}
+
+	return &Verifier{
+		keys: keys,
+		opts: opts,
+		now:  time.Now,
+	}
+}
+
+// Verify will return the claims of the token once its signature, expiry, issuer and audience are checked
+func (v *Verifier) Verify(token string) (Claims, error) {
+	unverified, parts, err := jwtlib.NewParser().ParseUnverified(token, jwtlib.MapClaims{})
+	if errors.Is(err, jwtlib.ErrTokenMalformed) {
+		return Claims{}, ErrMalformed
+	}
+	if _, decodeErr := jwtlib.DecodeSegment(parts[len(parts)-1]); decodeErr != nil {
+		return Claims{}, ErrMalformed
+	}
+	if err != nil || (unverified.Method.Alg() != HS256 && unverified.Method.Alg() != RS256) {
+		return Claims{}, ErrUnsupportedAlgorithm
+	}
+	alg := unverified.Method.Alg()
+	kid, _ := unverified.Header["kid"].(string)
+
+	// a key is only ever tried with its own algorithm so an RSA public key can't be abused as an HMAC secret
+	parser := jwtlib.NewParser(jwtlib.WithValidMethods([]string{HS256, RS256}), jwtlib.WithoutClaimsValidation())
+	for _, k := range v.keys {
+		if k.Algorithm != alg || (kid != "" && k.ID != "" && k.ID != kid) {
+			continue
+		}
+
+		raw := jwtlib.MapClaims{}
+		_, err := parser.ParseWithClaims(token, raw, k.verificationKey)
+		if err == nil {
+			return v.claims(raw)
+		}
+	}
+	return Claims{}, ErrInvalidSignature
+}
+
+func (v *Verifier) claims(raw jwtlib.MapClaims) (Claims, error) {
+	now := v.now()
+
+	if !raw.VerifyExpiresAt(now.Add(-v.opts.Leeway).Unix(), true) {
+		return Claims{}, ErrExpired
+	}
+	if !raw.VerifyNotBefore(now.Add(v.opts.Leeway).Unix(), false) {
+		return Claims{}, ErrNotValidYet
+	}
+	if v.opts.Issuer != "" && !raw.VerifyIssuer(v.opts.Issuer, true) {
+		return Claims{}, ErrInvalidIssuer
+	}
+	if v.opts.Audience != "" && !raw.VerifyAudience(v.opts.Audience, true) {
+		return Claims{}, ErrInvalidAudience
+	}
+
+	exp, _ := raw["exp"].(float64)
+	return Claims{
+		Subject:   stringClaim(raw["sub"]),
+		Issuer:    stringClaim(raw["iss"]),
+		Audience:  listClaim(raw["aud"], false),
+		ExpiresAt: time.Unix(0, int64(exp*float64(time.Second))),
+		Roles:     listClaim(raw[v.opts.RolesClaim], true),
+	}, nil
+}
+
+func stringClaim(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// listClaim accepts an array of strings or a single string, split on spaces when fields is set
+func listClaim(v interface{}, fields bool) []string {
+	switch val := v.(type) {
+	case string:
+		if fields {
+			return strings.Fields(val)
+		}
+		return []string{val}
+	case []interface{}:
+		list := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+type claimsKey struct{}
+
+// NewContext will return a copy of ctx carrying the verified claims
+func NewContext(ctx context.Context, c Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, c)
+}
+
+// FromContext return the verified claims carried by ctx, if any
+func FromContext(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(Claims)
+	return c, ok
+}
@@ -0,0 +1,217 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/jwt"
+)
+
+var secret = []byte("a-very-long-shared-secret-for-tests")
+
+func encode(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, header, claims map[string]interface{}, key []byte) string {
+	input := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, header, claims map[string]interface{}, key *rsa.PrivateKey) string {
+	input := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "jane",
+		"iss":   "https://auth.example.com",
+		"aud":   []string{"movie-api", "other"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"admin", "viewer"},
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v := jwt.NewVerifier([]jwt.Key{jwt.NewHMACKey("", secret)}, jwt.Options{
+		Issuer:   "https://auth.example.com",
+		Audience: "movie-api",
+		Leeway:   time.Minute,
+	})
+	hs256 := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+
+	t.Run("success", func(t *testing.T) {
+		claims, err := v.Verify(signHS256(t, hs256, validClaims(), secret))
+		require.NoError(t, err)
+		assert.Equal(t, "jane", claims.Subject)
+		assert.Equal(t, []string{"movie-api", "other"}, claims.Audience)
+		assert.True(t, claims.HasRole("admin"))
+		assert.False(t, claims.HasRole("owner"))
+	})
+
+	t.Run("space-separated-roles", func(t *testing.T) {
+		c := validClaims()
+		c["roles"] = "viewer admin"
+		c["aud"] = "movie-api"
+		claims, err := v.Verify(signHS256(t, hs256, c, secret))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"viewer", "admin"}, claims.Roles)
+	})
+
+	for name, tc := range map[string]struct {
+		token string
+		err   error
+	}{
+		"wrong-secret": {signHS256(t, hs256, validClaims(), []byte("guess")), jwt.ErrInvalidSignature},
+		"alg-none":     {encode(map[string]interface{}{"alg": "none"}) + "." + encode(validClaims()) + ".", jwt.ErrUnsupportedAlgorithm},
+		"alg-rs256":    {signHS256(t, map[string]interface{}{"alg": "RS256"}, validClaims(), secret), jwt.ErrInvalidSignature},
+		"malformed":    {"not.a-token", jwt.ErrMalformed},
+		"bad-base64":   {encode(map[string]interface{}{"alg": "HS256"}) + ".e30.!!", jwt.ErrMalformed},
+		"expired": {signHS256(t, hs256, func() map[string]interface{} {
+			c := validClaims()
+			c["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+			return c
+		}(), secret), jwt.ErrExpired},
+		"no-expiry": {signHS256(t, hs256, func() map[string]interface{} {
+			c := validClaims()
+			delete(c, "exp")
+			return c
+		}(), secret), jwt.ErrExpired},
+		"not-valid-yet": {signHS256(t, hs256, func() map[string]interface{} {
+			c := validClaims()
+			c["nbf"] = time.Now().Add(10 * time.Minute).Unix()
+			return c
+		}(), secret), jwt.ErrNotValidYet},
+		"wrong-issuer": {signHS256(t, hs256, func() map[string]interface{} {
+			c := validClaims()
+			c["iss"] = "https://evil.com"
+			return c
+		}(), secret), jwt.ErrInvalidIssuer},
+		"wrong-audience": {signHS256(t, hs256, func() map[string]interface{} {
+			c := validClaims()
+			c["aud"] = "billing-api"
+			return c
+		}(), secret), jwt.ErrInvalidAudience},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Verify(tc.token)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+
+	t.Run("leeway", func(t *testing.T) {
+		c := validClaims()
+		c["exp"] = time.Now().Add(-30 * time.Second).Unix()
+		_, err := v.Verify(signHS256(t, hs256, c, secret))
+		assert.NoError(t, err)
+	})
+}
+
+func TestVerifyRS256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	key, err := jwt.ParseRSAPublicKeyPEM("rsa-1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	v := jwt.NewVerifier([]jwt.Key{key, jwt.NewHMACKey("hmac-1", secret)}, jwt.Options{})
+
+	claims, err := v.Verify(signRS256(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, validClaims(), private))
+	require.NoError(t, err)
+	assert.Equal(t, "jane", claims.Subject)
+
+	_, err = v.Verify(signRS256(t, map[string]interface{}{"alg": "RS256", "kid": "hmac-1"}, validClaims(), private))
+	assert.Equal(t, jwt.ErrInvalidSignature, err)
+
+	// the RSA public key must not be accepted as an HMAC secret
+	_, err = v.Verify(signHS256(t, map[string]interface{}{"alg": "HS256", "kid": "rsa-1"}, validClaims(), der))
+	assert.Equal(t, jwt.ErrInvalidSignature, err)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&private.PublicKey)})
+	_, err = jwt.ParseRSAPublicKeyPEM("rsa-1", pkcs1)
+	assert.NoError(t, err)
+}
+
+func TestParseJWKS(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]interface{}{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+			},
+			{"kty": "oct", "kid": "hmac-1", "k": base64.RawURLEncoding.EncodeToString(secret)},
+			{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256"},
+		},
+	})
+	require.NoError(t, err)
+
+	keys, err := jwt.ParseJWKS(jwks)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, jwt.RS256, keys[0].Algorithm)
+	assert.Equal(t, jwt.HS256, keys[1].Algorithm)
+
+	v := jwt.NewVerifier(keys, jwt.Options{})
+	_, err = v.Verify(signRS256(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, validClaims(), private))
+	assert.NoError(t, err)
+	_, err = v.Verify(signHS256(t, map[string]interface{}{"alg": "HS256", "kid": "hmac-1"}, validClaims(), secret))
+	assert.NoError(t, err)
+
+	_, err = jwt.ParseJWKS([]byte(`{"keys":[]}`))
+	assert.Error(t, err)
+}
+
+func TestRejectWeakRSAKey(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, err = jwt.NewRSAKey("rsa-1", &private.PublicKey)
+	assert.Equal(t, jwt.ErrWeakKey, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	_, err = jwt.ParseRSAPublicKeyPEM("rsa-1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.True(t, errors.Is(err, jwt.ErrWeakKey))
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]interface{}{{
+			"kty": "RSA",
+			"kid": "rsa-1",
+			"n":   base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+	_, err = jwt.ParseJWKS(jwks)
+	assert.True(t, errors.Is(err, jwt.ErrWeakKey))
+}
@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	jwtlib "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// HS256 is HMAC using SHA-256, verified with a shared secret
+	HS256 = "HS256"
+	// RS256 is RSASSA-PKCS1-v1_5 using SHA-256, verified with an RSA public key
+	RS256 = "RS256"
+)
+
+// Key represent a key the tokens can be verified with, bound to a single algorithm
+type Key struct {
+	ID        string
+	Algorithm string
+
+	secret []byte
+	public *rsa.PublicKey
+}
+
+// NewHMACKey will create an HS256 Key from the shared secret
+func NewHMACKey(id string, secret []byte) Key {
+	return Key{ID: id, Algorithm: HS256, secret: secret}
+}
+
+// MinRSAKeyBits is the smallest RSA modulus accepted for RS256
+const MinRSAKeyBits = 2048
+
+// ErrWeakKey will throw if an RSA key is shorter than MinRSAKeyBits
+var ErrWeakKey = fmt.Errorf("jwt: RSA key is shorter than %d bits", MinRSAKeyBits)
+
+// NewRSAKey will create an RS256 Key from the public key, keys under MinRSAKeyBits are rejected
+func NewRSAKey(id string, public *rsa.PublicKey) (Key, error) {
+	if public == nil || public.N == nil || public.N.BitLen() < MinRSAKeyBits {
+		return Key{}, ErrWeakKey
+	}
+	return Key{ID: id, Algorithm: RS256, public: public}, nil
+}
+
+// verificationKey is the jwtlib.Keyfunc of the key, it refuses a token signed with another algorithm
+func (k Key) verificationKey(t *jwtlib.Token) (interface{}, error) {
+	if t.Method.Alg() != k.Algorithm {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if k.Algorithm == RS256 {
+		return k.public, nil
+	}
+	return k.secret, nil
+}
+
+// ParseRSAPublicKeyPEM will create an RS256 Key from a PEM encoded PKIX or PKCS#1 public key
+func ParseRSAPublicKeyPEM(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("jwt: no PEM block found")
+	}
+
+	if public, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return NewRSAKey(id, public)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("jwt: parse public key: %w", err)
+	}
+	public, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return Key{}, errors.New("jwt: public key is not an RSA key")
+	}
+	return NewRSAKey(id, public)
+}
+
+// LoadRSAPublicKeyPEM will read an RS256 Key from a PEM file
+func LoadRSAPublicKeyPEM(id, path string) (Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+	return ParseRSAPublicKeyPEM(id, data)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// ParseJWKS will create the Keys of a JSON Web Key Set. RSA keys are used for RS256 and symmetric keys for HS256,
+// keys meant for encryption or for another algorithm are skipped
+func ParseJWKS(data []byte) ([]Key, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwt: parse JWKS: %w", err)
+	}
+
+	var keys []Key
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		switch {
+		case k.Kty == "RSA" && (k.Alg == "" || k.Alg == RS256):
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: key %q: invalid modulus: %w", k.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("jwt: key %q: invalid exponent", k.Kid)
+			}
+			key, err := NewRSAKey(k.Kid, &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("jwt: key %q: %w", k.Kid, err)
+			}
+			keys = append(keys, key)
+		case k.Kty == "oct" && (k.Alg == "" || k.Alg == HS256):
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("jwt: key %q: invalid secret", k.Kid)
+			}
+			keys = append(keys, NewHMACKey(k.Kid, secret))
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: JWKS has no usable signing key")
+	}
+	return keys, nil
+}
+
+// LoadJWKS will read the Keys of a JSON Web Key Set file
+func LoadJWKS(path string) ([]Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
@@ -0,0 +1,93 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Component represent a subsystem whose lifetime is managed by the Registry.
+// Start must not block, long running work belongs in a goroutine started by it
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Registry will start its components in registration order and stop them in reverse,
+// so a component can rely on everything registered before it for its whole lifetime
+type Registry struct {
+	mu         sync.Mutex
+	components []Component
+	started    int
+	stopped    bool
+}
+
+// NewRegistry will create an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register will add the component to the registry, components without a Start are considered started
+func (r *Registry) Register(c Component) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.components = append(r.components, c)
+}
+
+// Start will start every component not started yet. When one fails the ones already started
+// are left running, the caller is expected to Stop the registry
+func (r *Registry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.started < len(r.components) {
+		c := r.components[r.started]
+		if c.Start != nil {
+			if err := c.Start(ctx); err != nil {
+				return fmt.Errorf("start %s: %w", c.Name, err)
+			}
+		}
+		r.started++
+		logrus.Infof("%s started", c.Name)
+	}
+
+	return nil
+}
+
+// Stop will stop the started components in reverse order. Every component is given
+// the chance to stop even when ctx is done or a previous one failed
+func (r *Registry) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		return nil
+	}
+	r.stopped = true
+
+	var errs []string
+	for i := r.started - 1; i >= 0; i-- {
+		c := r.components[i]
+		if c.Stop == nil {
+			continue
+		}
+
+		if err := c.Stop(ctx); err != nil {
+			logrus.Errorf("stop %s: %s", c.Name, err)
+			errs = append(errs, fmt.Sprintf("%s: %s", c.Name, err))
+			continue
+		}
+		logrus.Infof("%s stopped", c.Name)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("stop components: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/lifecycle"
+)
+
+func TestRegistry(t *testing.T) {
+	var calls []string
+	component := func(name string, stopErr error) lifecycle.Component {
+		return lifecycle.Component{
+			Name: name,
+			Start: func(ctx context.Context) error {
+				calls = append(calls, "start "+name)
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				calls = append(calls, "stop "+name)
+				return stopErr
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		calls = nil
+		r := lifecycle.NewRegistry()
+		r.Register(component("mysql", nil))
+		r.Register(component("cache", nil))
+		r.Register(component("http", nil))
+
+		assert.NoError(t, r.Start(context.TODO()))
+		assert.NoError(t, r.Stop(context.TODO()))
+		assert.NoError(t, r.Stop(context.TODO()))
+		assert.Equal(t, []string{
+			"start mysql", "start cache", "start http",
+			"stop http", "stop cache", "stop mysql",
+		}, calls)
+	})
+
+	t.Run("stop-error", func(t *testing.T) {
+		calls = nil
+		r := lifecycle.NewRegistry()
+		r.Register(component("mysql", nil))
+		r.Register(component("cache", errors.New("Unexpected")))
+
+		assert.NoError(t, r.Start(context.TODO()))
+		err := r.Stop(context.TODO())
+		assert.EqualError(t, err, "stop components: cache: Unexpected")
+		assert.Equal(t, []string{"start mysql", "start cache", "stop cache", "stop mysql"}, calls)
+	})
+
+	t.Run("start-error", func(t *testing.T) {
+		calls = nil
+		r := lifecycle.NewRegistry()
+		r.Register(component("mysql", nil))
+		r.Register(lifecycle.Component{
+			Name:  "http",
+			Start: func(ctx context.Context) error { return errors.New("address in use") },
+			Stop: func(ctx context.Context) error {
+				calls = append(calls, "stop http")
+				return nil
+			},
+		})
+
+		err := r.Start(context.TODO())
+		assert.EqualError(t, err, "start http: address in use")
+		assert.NoError(t, r.Stop(context.TODO()))
+		assert.Equal(t, []string{"start mysql", "stop mysql"}, calls)
+	})
+}
@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type entryKey struct{}
+
+// NewContext will return a copy of ctx carrying the given request scoped entry
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, entry)
+}
+
+// FromContext return the entry carried by ctx, or an entry of the standard logger when there is none,
+// so every layer can log with the fields of the request it serves
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(entryKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
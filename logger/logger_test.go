@@ -0,0 +1,21 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/logger"
+)
+
+func TestFromContext(t *testing.T) {
+	entry := logrus.NewEntry(logrus.New()).WithField("request_id", "abc")
+	ctx := logger.NewContext(context.TODO(), entry)
+	assert.Equal(t, entry, logger.FromContext(ctx))
+
+	fallback := logger.FromContext(context.TODO())
+	assert.Equal(t, logrus.StandardLogger(), fallback.Logger)
+	assert.Empty(t, fallback.Data)
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
+)
+
+// maxNum is the largest page of the log that can be requested
+const maxNum = 100
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// LogmovieHandler  represent the httphandler for the movie log
+type LogmovieHandler struct {
+	LUsecase domain.LogmovieUsecase
+}
+
+// NewLogmovieHandler will initialize the logs/ resources endpoint, authenticated by auth and guarded by the admin
+// middlewares. Without any admin guard the log is read-only, its PUT and DELETE routes aren't registered
+func NewLogmovieHandler(e *echo.Echo, us domain.LogmovieUsecase, auth []echo.MiddlewareFunc, admin ...echo.MiddlewareFunc) {
+	handler := &LogmovieHandler{
+		LUsecase: us,
+	}
+	m := append(append([]echo.MiddlewareFunc{}, auth...), admin...)
+	e.GET("/logs", handler.FetchLogmovie, m...)
+	e.GET("/logs/most-viewed", handler.MostViewed, m...)
+	e.GET("/logs/:id", handler.GetByID, m...)
+	if len(admin) == 0 {
+		return
+	}
+	e.PUT("/logs/:id", handler.Update, m...)
+	e.DELETE("/logs/:id", handler.Delete, m...)
+}
+
+// FetchLogmovie will fetch the movie log based on given params
+func (l *LogmovieHandler) FetchLogmovie(c echo.Context) error {
+	num, err := parseNum(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+
+	listLog, nextCursor, err := l.LUsecase.Fetch(ctx, cursor, num)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, listLog)
+}
+
+// MostViewed will fetch the most viewed movies of the log
+func (l *LogmovieHandler) MostViewed(c echo.Context) error {
+	num, err := parseNum(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+
+	listLog, err := l.LUsecase.MostViewed(ctx, num)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, listLog)
+}
+
+// GetByID will get the movie log by given id
+func (l *LogmovieHandler) GetByID(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	logmovie, err := l.LUsecase.GetByID(ctx, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, logmovie)
+}
+
+// parseNum will read the num query param, zero when missing so the usecase picks the default.
+// A num that isn't a number between 1 and maxNum is ErrBadParamInput
+func parseNum(c echo.Context) (int64, error) {
+	numS := c.QueryParam("num")
+	if numS == "" {
+		return 0, nil
+	}
+
+	num, err := strconv.ParseInt(numS, 10, 64)
+	if err != nil || num < 1 || num > maxNum {
+		return 0, domain.ErrBadParamInput
+	}
+	return num, nil
+}
+
+func isRequestValid(m *domain.Logmovie) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Update will update the movie log by given request body
+func (l *LogmovieHandler) Update(c echo.Context) (err error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	var logmovie domain.Logmovie
+	err = c.Bind(&logmovie)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&logmovie); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+
+	logmovie.ID = id
+	ctx := c.Request().Context()
+	err = l.LUsecase.Update(ctx, &logmovie)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	// the view count and the timestamps are the stored ones, not the ones of the request body
+	stored, err := l.LUsecase.GetByID(ctx, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, stored)
+}
+
+// Delete will delete the movie log by given id
+func (l *LogmovieHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	err = l.LUsecase.Delete(ctx, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// errorResponse will log the error with the request fields and write it with its matching status code
+func errorResponse(c echo.Context, err error) error {
+	logger.FromContext(c.Request().Context()).Error(err)
+
+	return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,230 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	logmovieHttp "github.com/bxcodec/go-clean-arch/logmovie/delivery/http"
+)
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.LogmovieUsecase)
+	mockListLog := []domain.Logmovie{{ID: 1, Title: "The Shawshank Redemption", ImdbID: "tt0111161"}}
+	mockUCase.On("Fetch", mock.Anything, "MQ==", int64(1)).Return(mockListLog, "Mg==", nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/logs?num=1&cursor=MQ==", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := logmovieHttp.LogmovieHandler{
+		LUsecase: mockUCase,
+	}
+	err = handler.FetchLogmovie(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Mg==", rec.Header().Get("X-Cursor"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestMostViewed(t *testing.T) {
+	mockUCase := new(mocks.LogmovieUsecase)
+	mockListLog := []domain.Logmovie{{ID: 1, Title: "The Shawshank Redemption", ImdbID: "tt0111161", ViewCount: 12}}
+	mockUCase.On("MostViewed", mock.Anything, int64(5)).Return(mockListLog, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/logs/most-viewed?num=5", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := logmovieHttp.LogmovieHandler{
+		LUsecase: mockUCase,
+	}
+	err = handler.MostViewed(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"view_count":12`)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchInvalidNum(t *testing.T) {
+	for _, num := range []string{"100000000", "0", "-1", "ten"} {
+		t.Run(num, func(t *testing.T) {
+			mockUCase := new(mocks.LogmovieUsecase)
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.GET, "/logs?num="+num, strings.NewReader(""))
+			assert.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			handler := logmovieHttp.LogmovieHandler{
+				LUsecase: mockUCase,
+			}
+			require.NoError(t, handler.FetchLogmovie(c))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			rec = httptest.NewRecorder()
+			require.NoError(t, handler.MostViewed(e.NewContext(req, rec)))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetByID(t *testing.T) {
+	mockUCase := new(mocks.LogmovieUsecase)
+	mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Logmovie{}, domain.ErrNotFound)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/logs/7", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("logs/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	handler := logmovieHttp.LogmovieHandler{
+		LUsecase: mockUCase,
+	}
+	err = handler.GetByID(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdate(t *testing.T) {
+	mockLogmovie := domain.Logmovie{
+		Title:      "The Shawshank Redemption",
+		Year:       "1994",
+		ImdbRating: "9.3",
+	}
+	j, err := json.Marshal(mockLogmovie)
+	assert.NoError(t, err)
+
+	mockUCase := new(mocks.LogmovieUsecase)
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(l *domain.Logmovie) bool {
+		return l.ID == 12 && l.Title == mockLogmovie.Title
+	})).Return(nil)
+	stored := mockLogmovie
+	stored.ID = 12
+	stored.ImdbID = "tt0111161"
+	stored.ViewCount = 42
+	mockUCase.On("GetByID", mock.Anything, int64(12)).Return(stored, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PUT, "/logs/12", strings.NewReader(string(j)))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("logs/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("12")
+	handler := logmovieHttp.LogmovieHandler{
+		LUsecase: mockUCase,
+	}
+	err = handler.Update(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var res domain.Logmovie
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, "tt0111161", res.ImdbID)
+	assert.Equal(t, int64(42), res.ViewCount)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdateInvalid(t *testing.T) {
+	mockUCase := new(mocks.LogmovieUsecase)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PUT, "/logs/12", strings.NewReader(`{"year":"1994"}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("logs/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("12")
+	handler := logmovieHttp.LogmovieHandler{
+		LUsecase: mockUCase,
+	}
+	err = handler.Update(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.LogmovieUsecase)
+	mockUCase.On("Delete", mock.Anything, int64(12)).Return(nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.DELETE, "/logs/12", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("logs/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("12")
+	handler := logmovieHttp.LogmovieHandler{
+		LUsecase: mockUCase,
+	}
+	err = handler.Delete(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestNewLogmovieHandlerRoutes(t *testing.T) {
+	routes := func(e *echo.Echo) map[string]bool {
+		found := make(map[string]bool)
+		for _, r := range e.Routes() {
+			found[r.Method+" "+r.Path] = true
+		}
+		return found
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		e := echo.New()
+		auth := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+		logmovieHttp.NewLogmovieHandler(e, new(mocks.LogmovieUsecase), []echo.MiddlewareFunc{auth})
+
+		found := routes(e)
+		assert.True(t, found["GET /logs/:id"])
+		assert.False(t, found["PUT /logs/:id"])
+		assert.False(t, found["DELETE /logs/:id"])
+	})
+
+	t.Run("guarded", func(t *testing.T) {
+		e := echo.New()
+		guard := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+		logmovieHttp.NewLogmovieHandler(e, new(mocks.LogmovieUsecase), nil, guard)
+
+		found := routes(e)
+		assert.True(t, found["PUT /logs/:id"])
+		assert.True(t, found["DELETE /logs/:id"])
+	})
+}
@@ -0,0 +1,201 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+var (
+	// ErrQueueFull will throw if the log queue is full and the writer is not configured to block
+	ErrQueueFull = errors.New("Movie log queue is full")
+	// ErrWriterClosed will throw if a movie is logged after the writer has been closed
+	ErrWriterClosed = errors.New("Movie log writer is closed")
+)
+
+// Options represent the buffering policy of the Writer
+type Options struct {
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	FlushTimeout  time.Duration
+	// Block makes Store wait for room in the queue instead of dropping the movie
+	Block bool
+}
+
+// Stats represent the counters of the Writer since it started
+type Stats struct {
+	Enqueued int64 `json:"enqueued"`
+	Dropped  int64 `json:"dropped"`
+	Flushed  int64 `json:"flushed"`
+	Failed   int64 `json:"failed"`
+	Pending  int   `json:"pending"`
+}
+
+// Writer is a domain.LogmovieRepository that queues Store calls and writes them
+// in batches from a background goroutine. Every other call goes straight to the wrapped repository
+type Writer struct {
+	domain.LogmovieRepository
+	opts Options
+
+	queue   chan domain.Movies
+	closing chan struct{}
+	done    chan struct{}
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+
+	enqueued int64
+	dropped  int64
+	flushed  int64
+	failed   int64
+}
+
+// NewBatchLogmovieRepository will start a Writer logging movies into repo.
+// Pending movies are flushed when the batch is full, on every flush interval and on Close
+func NewBatchLogmovieRepository(repo domain.LogmovieRepository, opts Options) *Writer {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1000
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.FlushTimeout <= 0 {
+		opts.FlushTimeout = 5 * time.Second
+	}
+
+	w := &Writer{
+		LogmovieRepository: repo,
+		opts:               opts,
+		queue:              make(chan domain.Movies, opts.BufferSize),
+		closing:            make(chan struct{}),
+		done:               make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+// Store will queue the movie to be logged by the next batch
+func (w *Writer) Store(ctx context.Context, m *domain.Movies) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	if !w.opts.Block {
+		select {
+		case w.queue <- *m:
+			atomic.AddInt64(&w.enqueued, 1)
+			return nil
+		default:
+			atomic.AddInt64(&w.dropped, 1)
+			return ErrQueueFull
+		}
+	}
+
+	select {
+	case w.queue <- *m:
+		atomic.AddInt64(&w.enqueued, 1)
+		return nil
+	case <-ctx.Done():
+		atomic.AddInt64(&w.dropped, 1)
+		return ctx.Err()
+	case <-w.closing:
+		atomic.AddInt64(&w.dropped, 1)
+		return ErrWriterClosed
+	}
+}
+
+// Stats return a snapshot of the writer counters
+func (w *Writer) Stats() Stats {
+	return Stats{
+		Enqueued: atomic.LoadInt64(&w.enqueued),
+		Dropped:  atomic.LoadInt64(&w.dropped),
+		Flushed:  atomic.LoadInt64(&w.flushed),
+		Failed:   atomic.LoadInt64(&w.failed),
+		Pending:  len(w.queue),
+	}
+}
+
+// Close will stop accepting movies and wait until the pending ones are written or ctx is done
+func (w *Writer) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		close(w.closing)
+		w.mu.Lock()
+		w.closed = true
+		close(w.queue)
+		w.mu.Unlock()
+	})
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.Movies, 0, w.opts.BatchSize)
+	for {
+		select {
+		case m, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+
+			batch = append(batch, m)
+			if len(batch) >= w.opts.BatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			w.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (w *Writer) flush(batch []domain.Movies) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.FlushTimeout)
+	defer cancel()
+
+	// the batch outlives the requests that queued it, so it starts a trace of its own
+	ctx, span := tracing.Start(ctx, "logmovie.flush")
+	span.SetAttributes(attribute.Int("batch.size", len(batch)))
+
+	err := w.LogmovieRepository.StoreBatch(ctx, batch)
+	tracing.Finish(span, &err)
+	if err != nil {
+		atomic.AddInt64(&w.failed, int64(len(batch)))
+		logrus.Errorf("flush %d movie logs: %s", len(batch), err)
+		return
+	}
+
+	atomic.AddInt64(&w.flushed, int64(len(batch)))
+}
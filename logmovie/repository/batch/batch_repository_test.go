@@ -0,0 +1,133 @@
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/logmovie/repository/batch"
+)
+
+var mockMovie = domain.Movies{ID: "tt0111161", Title: "The Shawshank Redemption", Year: "1994"}
+
+func TestStoreFlushOnBatchSize(t *testing.T) {
+	mockRepo := new(mocks.LogmovieRepository)
+	flushed := make(chan []domain.Movies, 1)
+	mockRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]domain.Movies")).
+		Run(func(args mock.Arguments) {
+			ms := args.Get(1).([]domain.Movies)
+			flushed <- append([]domain.Movies(nil), ms...)
+		}).Return(nil).Once()
+
+	w := batch.NewBatchLogmovieRepository(mockRepo, batch.Options{BatchSize: 2, FlushInterval: time.Hour})
+	defer w.Close(context.TODO())
+
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+
+	select {
+	case ms := <-flushed:
+		assert.Len(t, ms, 2)
+	case <-time.After(time.Second):
+		t.Fatal("batch was not flushed")
+	}
+	waitFor(t, func() bool { return w.Stats().Flushed == 2 })
+}
+
+func TestStoreFlushOnInterval(t *testing.T) {
+	mockRepo := new(mocks.LogmovieRepository)
+	mockRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]domain.Movies")).Return(nil).Once()
+
+	w := batch.NewBatchLogmovieRepository(mockRepo, batch.Options{BatchSize: 10, FlushInterval: 10 * time.Millisecond})
+	defer w.Close(context.TODO())
+
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+	waitFor(t, func() bool { return w.Stats().Flushed == 1 })
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStoreQueueFull(t *testing.T) {
+	mockRepo := new(mocks.LogmovieRepository)
+	release := make(chan struct{})
+	mockRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]domain.Movies")).
+		Run(func(args mock.Arguments) { <-release }).Return(nil)
+
+	w := batch.NewBatchLogmovieRepository(mockRepo, batch.Options{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour})
+
+	// the first movie keeps the consumer busy, the second one fills the queue
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+	waitFor(t, func() bool { return w.Stats().Pending == 0 })
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+
+	err := w.Store(context.TODO(), &mockMovie)
+	assert.Equal(t, batch.ErrQueueFull, err)
+	assert.Equal(t, int64(1), w.Stats().Dropped)
+
+	close(release)
+	assert.NoError(t, w.Close(context.TODO()))
+	assert.Equal(t, int64(2), w.Stats().Flushed)
+}
+
+func TestStoreBlock(t *testing.T) {
+	mockRepo := new(mocks.LogmovieRepository)
+	release := make(chan struct{})
+	mockRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]domain.Movies")).
+		Run(func(args mock.Arguments) { <-release }).Return(nil)
+
+	w := batch.NewBatchLogmovieRepository(mockRepo, batch.Options{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour, Block: true})
+
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+	waitFor(t, func() bool { return w.Stats().Pending == 0 })
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
+	err := w.Store(ctx, &mockMovie)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	close(release)
+	assert.NoError(t, w.Close(context.TODO()))
+}
+
+func TestCloseDrains(t *testing.T) {
+	mockRepo := new(mocks.LogmovieRepository)
+	mockRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]domain.Movies")).Return(errors.New("Unexpected")).Once()
+
+	w := batch.NewBatchLogmovieRepository(mockRepo, batch.Options{BatchSize: 10, FlushInterval: time.Hour})
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+	assert.NoError(t, w.Store(context.TODO(), &mockMovie))
+
+	assert.NoError(t, w.Close(context.TODO()))
+	assert.Equal(t, int64(2), w.Stats().Failed)
+	assert.Equal(t, batch.ErrWriterClosed, w.Store(context.TODO(), &mockMovie))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDelegates(t *testing.T) {
+	mockRepo := new(mocks.LogmovieRepository)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Logmovie{ID: 1}, nil).Once()
+
+	w := batch.NewBatchLogmovieRepository(mockRepo, batch.Options{})
+	defer w.Close(context.TODO())
+
+	l, err := w.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), l.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition was not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+// DecodeCursor will decode the last seen log id from the opaque cursor given by user.
+// An empty cursor starts from the beginning
+func DecodeCursor(encodedCursor string) (int64, error) {
+	if encodedCursor == "" {
+		return 0, nil
+	}
+
+	byt, err := base64.URLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode the last seen log id into an opaque cursor for user
+func EncodeCursor(id int64) string {
+	return base64.URLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
@@ -0,0 +1,124 @@
+package instrument
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logmovie/repository/batch"
+	"github.com/bxcodec/go-clean-arch/metrics"
+)
+
+type instrumentedLogmovieRepository struct {
+	repo     domain.LogmovieRepository
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewInstrumentedLogmovieRepository will count and time every query made through the given domain.LogmovieRepository
+func NewInstrumentedLogmovieRepository(repo domain.LogmovieRepository, reg prometheus.Registerer) (domain.LogmovieRepository, error) {
+	calls, err := metrics.NewCounterVec(reg, "logmovie_repository_calls_total",
+		"Queries made to the movie log by method and outcome.", "method", "outcome")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := metrics.NewHistogramVec(reg, "logmovie_repository_duration_seconds",
+		"Latency of the queries made to the movie log.", "method")
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedLogmovieRepository{
+		repo:     repo,
+		calls:    calls,
+		duration: duration,
+	}, nil
+}
+
+func (i *instrumentedLogmovieRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Logmovie, nextCursor string, err error) {
+	defer i.observe("Fetch", time.Now(), &err)
+	return i.repo.Fetch(ctx, cursor, num)
+}
+
+func (i *instrumentedLogmovieRepository) GetByID(ctx context.Context, id int64) (res domain.Logmovie, err error) {
+	defer i.observe("GetByID", time.Now(), &err)
+	return i.repo.GetByID(ctx, id)
+}
+
+func (i *instrumentedLogmovieRepository) MostViewed(ctx context.Context, num int64) (res []domain.Logmovie, err error) {
+	defer i.observe("MostViewed", time.Now(), &err)
+	return i.repo.MostViewed(ctx, num)
+}
+
+func (i *instrumentedLogmovieRepository) Store(ctx context.Context, m *domain.Movies) (err error) {
+	defer i.observe("Store", time.Now(), &err)
+	return i.repo.Store(ctx, m)
+}
+
+func (i *instrumentedLogmovieRepository) StoreBatch(ctx context.Context, ms []domain.Movies) (err error) {
+	defer i.observe("StoreBatch", time.Now(), &err)
+	return i.repo.StoreBatch(ctx, ms)
+}
+
+func (i *instrumentedLogmovieRepository) Update(ctx context.Context, l *domain.Logmovie) (err error) {
+	defer i.observe("Update", time.Now(), &err)
+	return i.repo.Update(ctx, l)
+}
+
+func (i *instrumentedLogmovieRepository) Delete(ctx context.Context, id int64) (err error) {
+	defer i.observe("Delete", time.Now(), &err)
+	return i.repo.Delete(ctx, id)
+}
+
+func (i *instrumentedLogmovieRepository) observe(method string, start time.Time, err *error) {
+	i.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	i.calls.WithLabelValues(method, metrics.Outcome(*err)).Inc()
+}
+
+// RegisterBatchWriter will expose the counters of the given batch.Writer
+func RegisterBatchWriter(reg prometheus.Registerer, w *batch.Writer) error {
+	err := reg.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "logmovie_writer_enqueued_total",
+		Help: "Movie lookups queued to be logged.",
+	}, func() float64 {
+		return float64(w.Stats().Enqueued)
+	}))
+	if err != nil {
+		return err
+	}
+	err = reg.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "logmovie_writer_dropped_total",
+		Help: "Movie lookups dropped because the log queue was full.",
+	}, func() float64 {
+		return float64(w.Stats().Dropped)
+	}))
+	if err != nil {
+		return err
+	}
+	err = reg.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "logmovie_writer_flushed_total",
+		Help: "Movie lookups written to the log.",
+	}, func() float64 {
+		return float64(w.Stats().Flushed)
+	}))
+	if err != nil {
+		return err
+	}
+	err = reg.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "logmovie_writer_failed_total",
+		Help: "Movie lookups lost because their batch failed to be written.",
+	}, func() float64 {
+		return float64(w.Stats().Failed)
+	}))
+	if err != nil {
+		return err
+	}
+	return reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "logmovie_writer_pending",
+		Help: "Movie lookups waiting in the log queue.",
+	}, func() float64 {
+		return float64(w.Stats().Pending)
+	}))
+}
@@ -0,0 +1,62 @@
+package instrument_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/logmovie/repository/batch"
+	"github.com/bxcodec/go-clean-arch/logmovie/repository/instrument"
+)
+
+func TestInstrumentedLogmovieRepository(t *testing.T) {
+	mockLogmovieRepo := new(mocks.LogmovieRepository)
+	mockLogmovieRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]domain.Movies")).Return(errors.New("Unexpected")).Once()
+	mockLogmovieRepo.On("Delete", mock.Anything, int64(12)).Return(nil).Once()
+
+	reg := prometheus.NewRegistry()
+	repo, err := instrument.NewInstrumentedLogmovieRepository(mockLogmovieRepo, reg)
+	require.NoError(t, err)
+
+	err = repo.StoreBatch(context.TODO(), []domain.Movies{{ID: "tt0111161"}})
+	assert.Error(t, err)
+	err = repo.Delete(context.TODO(), 12)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	buf := rec.Body
+	assert.Contains(t, buf.String(), `logmovie_repository_calls_total{method="StoreBatch",outcome="error"} 1`)
+	assert.Contains(t, buf.String(), `logmovie_repository_calls_total{method="Delete",outcome="ok"} 1`)
+	assert.Contains(t, buf.String(), `logmovie_repository_duration_seconds_count{method="StoreBatch"} 1`)
+	mockLogmovieRepo.AssertExpectations(t)
+}
+
+func TestRegisterBatchWriter(t *testing.T) {
+	mockLogmovieRepo := new(mocks.LogmovieRepository)
+	mockLogmovieRepo.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]domain.Movies")).Return(nil).Once()
+
+	w := batch.NewBatchLogmovieRepository(mockLogmovieRepo, batch.Options{})
+	reg := prometheus.NewRegistry()
+	require.NoError(t, instrument.RegisterBatchWriter(reg, w))
+
+	assert.NoError(t, w.Store(context.TODO(), &domain.Movies{ID: "tt0111161"}))
+	assert.NoError(t, w.Close(context.TODO()))
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	buf := rec.Body
+	assert.Contains(t, buf.String(), "logmovie_writer_enqueued_total 1\n")
+	assert.Contains(t, buf.String(), "logmovie_writer_flushed_total 1\n")
+	assert.Contains(t, buf.String(), "logmovie_writer_pending 0\n")
+}
@@ -3,8 +3,16 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
+	"github.com/bxcodec/go-clean-arch/logmovie/repository"
+	"github.com/bxcodec/go-clean-arch/tracing"
 )
 
 type mysqlLogmovieRepo struct {
@@ -18,21 +26,175 @@ func NewMysqlLogmovieRepository(db *sql.DB) domain.LogmovieRepository {
 	}
 }
 
-func (mm *mysqlLogmovieRepo) Store(ctx context.Context, m *domain.Movies) (err error) {
-	query := `INSERT movies SET title=? , imdbID=? , year=?, released=? , imdbRating=?`
-	stmt, err := mm.DB.PrepareContext(ctx, query)
+// startSpan will trace the given query of the movie log as a client span of the request
+func startSpan(ctx context.Context, method, query string) (context.Context, trace.Span) {
+	ctx, span := tracing.Start(ctx, "mysqlLogmovieRepo."+method, trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("db.system", "mysql"),
+		attribute.String("db.statement", query),
+	)
+	return ctx, span
+}
+
+func (mm *mysqlLogmovieRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Logmovie, err error) {
+	log := logger.FromContext(ctx)
+	rows, err := mm.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			log.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Logmovie, 0)
+	for rows.Next() {
+		l := domain.Logmovie{}
+		var released, imdbRating sql.NullString
+		err = rows.Scan(
+			&l.ID,
+			&l.Title,
+			&l.ImdbID,
+			&l.Year,
+			&released,
+			&imdbRating,
+			&l.ViewCount,
+			&l.LastViewedAt,
+		)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+
+		l.Released = released.String
+		l.ImdbRating = imdbRating.String
+		result = append(result, l)
+	}
+
+	return result, rows.Err()
+}
+
+func (mm *mysqlLogmovieRepo) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Logmovie, nextCursor string, err error) {
+	query := `SELECT id, title, imdbID, year, released, imdbRating, view_count, last_viewed_at FROM movies WHERE id > ? ORDER BY id LIMIT ?`
+	ctx, span := startSpan(ctx, "Fetch", query)
+	defer tracing.Finish(span, &err)
+
+	lastID, err := repository.DecodeCursor(cursor)
 	if err != nil {
+		err = domain.ErrBadParamInput
+		return
+	}
+
+	res, err = mm.fetch(ctx, query, lastID, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if int64(len(res)) == num {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].ID)
+	}
+
+	return
+}
+
+func (mm *mysqlLogmovieRepo) GetByID(ctx context.Context, id int64) (res domain.Logmovie, err error) {
+	query := `SELECT id, title, imdbID, year, released, imdbRating, view_count, last_viewed_at FROM movies WHERE id = ?`
+	ctx, span := startSpan(ctx, "GetByID", query)
+	defer tracing.Finish(span, &err)
+
+	list, err := mm.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Logmovie{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (mm *mysqlLogmovieRepo) MostViewed(ctx context.Context, num int64) (res []domain.Logmovie, err error) {
+	query := `SELECT id, title, imdbID, year, released, imdbRating, view_count, last_viewed_at FROM movies
+		ORDER BY view_count DESC, last_viewed_at DESC LIMIT ?`
+	ctx, span := startSpan(ctx, "MostViewed", query)
+	defer tracing.Finish(span, &err)
+
+	return mm.fetch(ctx, query, num)
+}
+
+// Store will log the given movie once per imdbID, a movie already logged gets its view counter bumped
+func (mm *mysqlLogmovieRepo) Store(ctx context.Context, m *domain.Movies) (err error) {
+	query := `INSERT INTO movies (title, imdbID, year, released, imdbRating, view_count, last_viewed_at)
+		VALUES (?, ?, ?, ?, ?, 1, ?)
+		ON DUPLICATE KEY UPDATE title=VALUES(title), year=VALUES(year), released=VALUES(released),
+		imdbRating=VALUES(imdbRating), view_count=view_count+1, last_viewed_at=VALUES(last_viewed_at)`
+	ctx, span := startSpan(ctx, "Store", query)
+	defer tracing.Finish(span, &err)
+
+	_, err = mm.DB.ExecContext(ctx, query, m.Title, m.ID, m.Year, m.Released, m.ImdbRating, time.Now())
+	return
+}
+
+// StoreBatch will log the given movies with a single multi-row upsert
+func (mm *mysqlLogmovieRepo) StoreBatch(ctx context.Context, ms []domain.Movies) (err error) {
+	if len(ms) == 0 {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, m.Title, m.ID, m.Year, m.Released, m.ImdbRating)
+	now := time.Now()
+	placeholders := make([]string, 0, len(ms))
+	args := make([]interface{}, 0, len(ms)*6)
+	for _, m := range ms {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, 1, ?)")
+		args = append(args, m.Title, m.ID, m.Year, m.Released, m.ImdbRating, now)
+	}
+
+	query := `INSERT INTO movies (title, imdbID, year, released, imdbRating, view_count, last_viewed_at)
+		VALUES ` + strings.Join(placeholders, ", ") + `
+		ON DUPLICATE KEY UPDATE title=VALUES(title), year=VALUES(year), released=VALUES(released),
+		imdbRating=VALUES(imdbRating), view_count=view_count+1, last_viewed_at=VALUES(last_viewed_at)`
+	ctx, span := startSpan(ctx, "StoreBatch", query)
+	defer tracing.Finish(span, &err)
+	span.SetAttributes(attribute.Int("db.rows", len(ms)))
+
+	_, err = mm.DB.ExecContext(ctx, query, args...)
+	return
+}
+
+func (mm *mysqlLogmovieRepo) Update(ctx context.Context, l *domain.Logmovie) (err error) {
+	query := `UPDATE movies SET title=?, year=?, released=?, imdbRating=? WHERE id = ?`
+	ctx, span := startSpan(ctx, "Update", query)
+	defer tracing.Finish(span, &err)
+
+	_, err = mm.DB.ExecContext(ctx, query, l.Title, l.Year, l.Released, l.ImdbRating, l.ID)
+	return
+}
+
+func (mm *mysqlLogmovieRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := `DELETE FROM movies WHERE id = ?`
+	ctx, span := startSpan(ctx, "Delete", query)
+	defer tracing.Finish(span, &err)
+
+	res, err := mm.DB.ExecContext(ctx, query, id)
 	if err != nil {
 		return
 	}
-	_, err = res.LastInsertId()
+
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return
 	}
 
+	if rowsAffected == 0 {
+		err = domain.ErrNotFound
+	}
+
 	return
 }
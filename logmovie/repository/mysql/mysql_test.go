@@ -8,27 +8,177 @@ import (
 	"github.com/stretchr/testify/assert"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
-	repository "github.com/bxcodec/go-clean-arch/logmovie/repository/mysql"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logmovie/repository"
+	repositoryMysql "github.com/bxcodec/go-clean-arch/logmovie/repository/mysql"
 )
 
+var columns = []string{"id", "title", "imdbID", "year", "released", "imdbRating", "view_count", "last_viewed_at"}
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(3, "Batman Begins", "tt0372784", "2005", "15 Jun 2005", "8.2", 2, time.Now()).
+		AddRow(4, "The Dark Knight", "tt0468569", "2008", nil, nil, 1, time.Now())
+
+	query := "SELECT id, title, imdbID, year, released, imdbRating, view_count, last_viewed_at FROM movies WHERE id > \\? ORDER BY id LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(int64(2), int64(2)).WillReturnRows(rows)
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
+	cursor := repository.EncodeCursor(2)
+	num := int64(2)
+	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, repository.EncodeCursor(4), nextCursor)
+	assert.Equal(t, "", list[1].Released)
+}
+
 func TestGetByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "updated_at", "created_at"}).
-		AddRow(1, "Iman Tumorang", time.Now(), time.Now())
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "The Shawshank Redemption", "tt0111161", "1994", "14 Oct 1994", "9.3", 5, time.Now())
+
+	query := "SELECT id, title, imdbID, year, released, imdbRating, view_count, last_viewed_at FROM movies WHERE id = \\?"
+
+	mock.ExpectQuery(query).WithArgs(int64(1)).WillReturnRows(rows)
+
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
+
+	anmovie, err := a.GetByID(context.TODO(), int64(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "tt0111161", anmovie.ImdbID)
+}
+
+func TestGetByIDNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, title, imdbID, year, released, imdbRating, view_count, last_viewed_at FROM movies WHERE id = \\?"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(sqlmock.NewRows(columns))
+
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
+
+	_, err = a.GetByID(context.TODO(), int64(7))
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestMostViewed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "The Shawshank Redemption", "tt0111161", "1994", "14 Oct 1994", "9.3", 12, time.Now()).
+		AddRow(3, "Batman Begins", "tt0372784", "2005", "15 Jun 2005", "8.2", 4, time.Now())
+
+	query := "SELECT (.+) FROM movies\\s+ORDER BY view_count DESC, last_viewed_at DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(int64(2)).WillReturnRows(rows)
+
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
+
+	list, err := a.MostViewed(context.TODO(), int64(2))
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, int64(12), list[0].ViewCount)
+}
+
+func TestStore(t *testing.T) {
+	m := &domain.Movies{
+		ID:         "tt0111161",
+		Title:      "The Shawshank Redemption",
+		Year:       "1994",
+		Released:   "14 Oct 1994",
+		ImdbRating: "9.3",
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO movies \\(title, imdbID, year, released, imdbRating, view_count, last_viewed_at\\)" +
+		"(.|\\s)+ON DUPLICATE KEY UPDATE(.|\\s)+view_count=view_count\\+1"
+	mock.ExpectExec(query).WithArgs(m.Title, m.ID, m.Year, m.Released, m.ImdbRating, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(12, 1))
+
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
+
+	err = a.Store(context.TODO(), m)
+	assert.NoError(t, err)
+}
+
+func TestStoreBatch(t *testing.T) {
+	ms := []domain.Movies{
+		{ID: "tt0111161", Title: "The Shawshank Redemption", Year: "1994", Released: "14 Oct 1994", ImdbRating: "9.3"},
+		{ID: "tt0372784", Title: "Batman Begins", Year: "2005", Released: "15 Jun 2005", ImdbRating: "8.2"},
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO movies \\(title, imdbID, year, released, imdbRating, view_count, last_viewed_at\\)" +
+		"\\s+VALUES \\(\\?, \\?, \\?, \\?, \\?, 1, \\?\\), \\(\\?, \\?, \\?, \\?, \\?, 1, \\?\\)" +
+		"(.|\\s)+ON DUPLICATE KEY UPDATE(.|\\s)+view_count=view_count\\+1"
+	mock.ExpectExec(query).
+		WithArgs(ms[0].Title, ms[0].ID, ms[0].Year, ms[0].Released, ms[0].ImdbRating, sqlmock.AnyArg(),
+			ms[1].Title, ms[1].ID, ms[1].Year, ms[1].Released, ms[1].ImdbRating, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(13, 2))
+
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
+
+	err = a.StoreBatch(context.TODO(), ms)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	l := &domain.Logmovie{
+		ID:         12,
+		Title:      "The Shawshank Redemption",
+		Year:       "1994",
+		Released:   "14 Oct 1994",
+		ImdbRating: "9.3",
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE movies SET title=\\?, year=\\?, released=\\?, imdbRating=\\? WHERE id = \\?"
+
+	mock.ExpectExec(query).WithArgs(l.Title, l.Year, l.Released, l.ImdbRating, l.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
+
+	err = a.Update(context.TODO(), l)
+	assert.NoError(t, err)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
 
-	query := "SELECT id, name, created_at, updated_at FROM movies WHERE id=\\?"
+	query := "DELETE FROM movies WHERE id = \\?"
 
-	prep := mock.ExpectPrepare(query)
-	userID := int64(1)
-	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
+	mock.ExpectExec(query).WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
 
-	a := repository.NewMysqlLogmovieRepository(db)
+	a := repositoryMysql.NewMysqlLogmovieRepository(db)
 
-	anmovie, err := a.GetByID(context.TODO(), userID)
+	num := int64(12)
+	err = a.Delete(context.TODO(), num)
 	assert.NoError(t, err)
-	assert.NotNil(t, anmovie)
 }
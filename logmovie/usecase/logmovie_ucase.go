@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type logmovieUsecase struct {
+	logmovieRepo   domain.LogmovieRepository
+	contextTimeout time.Duration
+}
+
+// NewLogmovieUsecase will create new a logmovieUsecase object representation of domain.LogmovieUsecase interface
+func NewLogmovieUsecase(l domain.LogmovieRepository, timeout time.Duration) domain.LogmovieUsecase {
+	return &logmovieUsecase{
+		logmovieRepo:   l,
+		contextTimeout: timeout,
+	}
+}
+
+func (l *logmovieUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.Logmovie, nextCursor string, err error) {
+	if num <= 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = l.logmovieRepo.Fetch(ctx, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return res, nextCursor, nil
+}
+
+func (l *logmovieUsecase) GetByID(c context.Context, id int64) (res domain.Logmovie, err error) {
+	ctx, cancel := context.WithTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	return l.logmovieRepo.GetByID(ctx, id)
+}
+
+func (l *logmovieUsecase) MostViewed(c context.Context, num int64) (res []domain.Logmovie, err error) {
+	if num <= 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	return l.logmovieRepo.MostViewed(ctx, num)
+}
+
+func (l *logmovieUsecase) Update(c context.Context, lm *domain.Logmovie) (err error) {
+	ctx, cancel := context.WithTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	_, err = l.logmovieRepo.GetByID(ctx, lm.ID)
+	if err != nil {
+		return
+	}
+
+	return l.logmovieRepo.Update(ctx, lm)
+}
+
+func (l *logmovieUsecase) Delete(c context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	_, err = l.logmovieRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	return l.logmovieRepo.Delete(ctx, id)
+}
@@ -0,0 +1,104 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	ucase "github.com/bxcodec/go-clean-arch/logmovie/usecase"
+)
+
+func TestFetch(t *testing.T) {
+	mockLogmovieRepo := new(mocks.LogmovieRepository)
+	mockListLog := []domain.Logmovie{{ID: 1, Title: "The Shawshank Redemption", ImdbID: "tt0111161"}}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogmovieRepo.On("Fetch", mock.Anything, "12", int64(10)).Return(mockListLog, "next-cursor", nil).Once()
+		u := ucase.NewLogmovieUsecase(mockLogmovieRepo, time.Second*2)
+
+		list, nextCursor, err := u.Fetch(context.TODO(), "12", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", nextCursor)
+		assert.Len(t, list, len(mockListLog))
+		mockLogmovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockLogmovieRepo.On("Fetch", mock.Anything, "12", int64(5)).Return(nil, "", errors.New("Unexpexted Error")).Once()
+		u := ucase.NewLogmovieUsecase(mockLogmovieRepo, time.Second*2)
+
+		list, nextCursor, err := u.Fetch(context.TODO(), "12", 5)
+		assert.Error(t, err)
+		assert.Empty(t, nextCursor)
+		assert.Len(t, list, 0)
+		mockLogmovieRepo.AssertExpectations(t)
+	})
+}
+
+func TestMostViewed(t *testing.T) {
+	mockLogmovieRepo := new(mocks.LogmovieRepository)
+	mockListLog := []domain.Logmovie{{ID: 1, Title: "The Shawshank Redemption", ImdbID: "tt0111161", ViewCount: 12}}
+	mockLogmovieRepo.On("MostViewed", mock.Anything, int64(10)).Return(mockListLog, nil).Once()
+	u := ucase.NewLogmovieUsecase(mockLogmovieRepo, time.Second*2)
+
+	list, err := u.MostViewed(context.TODO(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, mockListLog, list)
+	mockLogmovieRepo.AssertExpectations(t)
+}
+
+func TestUpdate(t *testing.T) {
+	mockLogmovie := domain.Logmovie{ID: 1, Title: "The Shawshank Redemption", ImdbID: "tt0111161"}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogmovieRepo := new(mocks.LogmovieRepository)
+		mockLogmovieRepo.On("GetByID", mock.Anything, mockLogmovie.ID).Return(mockLogmovie, nil).Once()
+		mockLogmovieRepo.On("Update", mock.Anything, &mockLogmovie).Return(nil).Once()
+		u := ucase.NewLogmovieUsecase(mockLogmovieRepo, time.Second*2)
+
+		err := u.Update(context.TODO(), &mockLogmovie)
+		assert.NoError(t, err)
+		mockLogmovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockLogmovieRepo := new(mocks.LogmovieRepository)
+		mockLogmovieRepo.On("GetByID", mock.Anything, mockLogmovie.ID).Return(domain.Logmovie{}, domain.ErrNotFound).Once()
+		u := ucase.NewLogmovieUsecase(mockLogmovieRepo, time.Second*2)
+
+		err := u.Update(context.TODO(), &mockLogmovie)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockLogmovieRepo.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockLogmovie := domain.Logmovie{ID: 1, Title: "The Shawshank Redemption", ImdbID: "tt0111161"}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogmovieRepo := new(mocks.LogmovieRepository)
+		mockLogmovieRepo.On("GetByID", mock.Anything, mockLogmovie.ID).Return(mockLogmovie, nil).Once()
+		mockLogmovieRepo.On("Delete", mock.Anything, mockLogmovie.ID).Return(nil).Once()
+		u := ucase.NewLogmovieUsecase(mockLogmovieRepo, time.Second*2)
+
+		err := u.Delete(context.TODO(), mockLogmovie.ID)
+		assert.NoError(t, err)
+		mockLogmovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockLogmovieRepo := new(mocks.LogmovieRepository)
+		mockLogmovieRepo.On("GetByID", mock.Anything, mockLogmovie.ID).Return(domain.Logmovie{}, domain.ErrNotFound).Once()
+		u := ucase.NewLogmovieUsecase(mockLogmovieRepo, time.Second*2)
+
+		err := u.Delete(context.TODO(), mockLogmovie.ID)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockLogmovieRepo.AssertExpectations(t)
+	})
+}
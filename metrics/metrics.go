@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewCounterVec will register a counter partitioned by the given labels with reg. A counter already registered
+// with the same help and labels is returned instead, e.g. to the decorators of several providers
+func NewCounterVec(reg prometheus.Registerer, name, help string, labels ...string) (*prometheus.CounterVec, error) {
+	c, err := register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels))
+	if err != nil {
+		return nil, err
+	}
+	vec, ok := c.(*prometheus.CounterVec)
+	if !ok {
+		return nil, fmt.Errorf("metrics: %s is already registered as another kind of metric", name)
+	}
+	return vec, nil
+}
+
+// NewHistogramVec will register a histogram of the default buckets partitioned by the given labels with reg.
+// A histogram already registered with the same help and labels is returned instead
+func NewHistogramVec(reg prometheus.Registerer, name, help string, labels ...string) (*prometheus.HistogramVec, error) {
+	c, err := register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help}, labels))
+	if err != nil {
+		return nil, err
+	}
+	vec, ok := c.(*prometheus.HistogramVec)
+	if !ok {
+		return nil, fmt.Errorf("metrics: %s is already registered as another kind of metric", name)
+	}
+	return vec, nil
+}
+
+// register will register c with reg, or return the collector already registered with the same description
+func register(reg prometheus.Registerer, c prometheus.Collector) (prometheus.Collector, error) {
+	err := reg.Register(c)
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		return registered.ExistingCollector, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,47 @@
+package metrics_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/metrics"
+)
+
+func TestNewCounterVec(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	requests, err := metrics.NewCounterVec(reg, "requests_total", "Requests served.", "code")
+	require.NoError(t, err)
+
+	// the same counter is shared
+	again, err := metrics.NewCounterVec(reg, "requests_total", "Requests served.", "code")
+	require.NoError(t, err)
+	assert.True(t, requests == again)
+
+	_, err = metrics.NewCounterVec(reg, "requests_total", "Requests served.", "route")
+	assert.Error(t, err)
+	_, err = metrics.NewHistogramVec(reg, "requests_total", "Requests served.", "code")
+	assert.Error(t, err)
+}
+
+func TestNewHistogramVec(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	latency, err := metrics.NewHistogramVec(reg, "latency_seconds", "Latency.", "op")
+	require.NoError(t, err)
+
+	again, err := metrics.NewHistogramVec(reg, "latency_seconds", "Latency.", "op")
+	require.NoError(t, err)
+	assert.True(t, latency == again)
+}
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, "ok", metrics.Outcome(nil))
+	assert.Equal(t, "not_found", metrics.Outcome(domain.ErrNotFound))
+	assert.Equal(t, "rate_limited", metrics.Outcome(&domain.RateLimitError{}))
+	assert.Equal(t, "unavailable", metrics.Outcome(domain.ErrServiceUnavailable))
+	assert.Equal(t, "error", metrics.Outcome(errors.New("Unexpected")))
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Outcome will map the error returned by a call to a low cardinality label value
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrCacheMiss):
+		return "not_found"
+	case errors.Is(err, domain.ErrBadParamInput), errors.Is(err, domain.ErrTooManyResults):
+		return "bad_request"
+	case errors.Is(err, domain.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, domain.ErrServiceUnavailable):
+		return "unavailable"
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return "unauthorized"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
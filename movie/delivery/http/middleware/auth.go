@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/jwt"
+	"github.com/bxcodec/go-clean-arch/logger"
+)
+
+// HeaderAPIKey is the request header carrying the API key of the client
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyAuth will authenticate the client from its API key, consume one request from its budgets and store it
+// in the request context. The requests already authenticated by JWTAuth need no key, the client of their token
+// is held to the budgets instead. It is meant to guard routes, an unknown path is still answered with 404
+func APIKeyAuth(us domain.ClientUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			var client domain.Client
+			var err error
+			apiKey := c.Request().Header.Get(HeaderAPIKey)
+			if claims, ok := jwt.FromContext(ctx); ok && apiKey == "" {
+				client, err = us.AuthenticateToken(ctx, claims.Subject)
+			} else {
+				client, err = us.Authenticate(ctx, apiKey)
+			}
+			if err != nil {
+				return authError(c, err)
+			}
+
+			ctx = domain.NewClientContext(ctx, client)
+			ctx = logger.NewContext(ctx, logger.FromContext(ctx).WithField("client_id", client.ID))
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			if err = us.Allow(ctx, client); err != nil {
+				return authError(c, err)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// authError will turn err into an *echo.HTTPError, which is written in the ResponseError shape
+func authError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorized):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	var rateLimitErr *domain.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		retryAfter := int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	}
+
+	logger.FromContext(c.Request().Context()).Error(err)
+	return echo.NewHTTPError(http.StatusInternalServerError, domain.ErrInternalServerError.Error())
+}
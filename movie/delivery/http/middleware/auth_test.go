@@ -0,0 +1,125 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/delivery/http/middleware"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	mockClient := domain.Client{ID: 7, Name: "frontend", Active: true}
+
+	newServer := func(us domain.ClientUsecase) *echo.Echo {
+		e := echo.New()
+		e.GET("/movies", func(c echo.Context) error {
+			client, ok := domain.ClientFromContext(c.Request().Context())
+			require.True(t, ok)
+			return c.JSON(http.StatusOK, client.Name)
+		}, middleware.APIKeyAuth(us))
+		e.GET("/healthz", func(c echo.Context) error {
+			_, ok := domain.ClientFromContext(c.Request().Context())
+			assert.False(t, ok)
+			return c.NoContent(http.StatusOK)
+		})
+		return e
+	}
+
+	serve := func(e *echo.Echo, path, key string) *test.ResponseRecorder {
+		req := test.NewRequest(echo.GET, path, nil)
+		if key != "" {
+			req.Header.Set(middleware.HeaderAPIKey, key)
+		}
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+		return res
+	}
+
+	message := func(t *testing.T, res *test.ResponseRecorder) string {
+		var body struct {
+			Message string `json:"message"`
+		}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		return body.Message
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("Authenticate", mock.Anything, "secret").Return(mockClient, nil).Once()
+		mockClientUCase.On("Allow", mock.Anything, mockClient).Return(nil).Once()
+
+		res := serve(newServer(mockClientUCase), "/movies", "secret")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `"frontend"`, res.Body.String())
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("public-route", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+
+		res := serve(newServer(mockClientUCase), "/healthz", "")
+		assert.Equal(t, http.StatusOK, res.Code)
+		mockClientUCase.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown-route", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+
+		res := serve(newServer(mockClientUCase), "/unknown", "")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		mockClientUCase.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("Authenticate", mock.Anything, "").Return(domain.Client{}, domain.ErrUnauthorized).Once()
+
+		res := serve(newServer(mockClientUCase), "/movies", "")
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, domain.ErrUnauthorized.Error(), message(t, res))
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("Authenticate", mock.Anything, "revoked").Return(domain.Client{}, domain.ErrForbidden).Once()
+
+		res := serve(newServer(mockClientUCase), "/movies", "revoked")
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.ErrForbidden.Error(), message(t, res))
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("rate-limited", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("Authenticate", mock.Anything, "secret").Return(mockClient, nil).Once()
+		mockClientUCase.On("Allow", mock.Anything, mockClient).Return(&domain.RateLimitError{RetryAfter: 1500 * time.Millisecond}).Once()
+
+		res := serve(newServer(mockClientUCase), "/movies", "secret")
+		assert.Equal(t, http.StatusTooManyRequests, res.Code)
+		assert.Equal(t, "2", res.Header().Get("Retry-After"))
+		assert.Equal(t, domain.ErrRateLimited.Error(), message(t, res))
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("Authenticate", mock.Anything, "secret").Return(domain.Client{}, errors.New("Unexpected Error")).Once()
+
+		res := serve(newServer(mockClientUCase), "/movies", "secret")
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, domain.ErrInternalServerError.Error(), message(t, res))
+		mockClientUCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/jwt"
+	"github.com/bxcodec/go-clean-arch/logger"
+)
+
+const bearerChallenge = `Bearer error="invalid_token"`
+
+// JWTAuth will verify the bearer token of the requests carrying one and store its claims in the request context.
+// Requests without a token go through untouched, an invalid token is answered with 401
+func JWTAuth(v *jwt.Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+			if authorization == "" {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			const prefix = "bearer "
+			if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, bearerChallenge)
+				return echo.NewHTTPError(http.StatusUnauthorized, domain.ErrInvalidToken.Error())
+			}
+
+			claims, err := v.Verify(strings.TrimSpace(authorization[len(prefix):]))
+			if err != nil {
+				logger.FromContext(ctx).Warnf("bearer token rejected: %s", err)
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, bearerChallenge)
+				return echo.NewHTTPError(http.StatusUnauthorized, domain.ErrInvalidToken.Error())
+			}
+
+			ctx = jwt.NewContext(ctx, claims)
+			ctx = logger.NewContext(ctx, logger.FromContext(ctx).WithField("subject", claims.Subject))
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// RequireRole will only let through the requests whose bearer token grants the role,
+// answering 401 to requests without a token and 403 to the others
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := jwt.FromContext(c.Request().Context())
+			if !ok {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return echo.NewHTTPError(http.StatusUnauthorized, domain.ErrInvalidToken.Error())
+			}
+			if !claims.HasRole(role) {
+				return echo.NewHTTPError(http.StatusForbidden, domain.ErrForbidden.Error())
+			}
+
+			return next(c)
+		}
+	}
+}
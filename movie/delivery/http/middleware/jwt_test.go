@@ -0,0 +1,165 @@
+package middleware_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/jwt"
+	"github.com/bxcodec/go-clean-arch/movie/delivery/http/middleware"
+)
+
+var jwtSecret = []byte("a-very-long-shared-secret-for-tests")
+
+func signToken(claims map[string]interface{}) string {
+	segment := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := segment(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + segment(claims)
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTAuth(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	adminToken := signToken(map[string]interface{}{"sub": "jane", "exp": exp, "roles": []string{"admin"}})
+	viewerToken := signToken(map[string]interface{}{"sub": "john", "exp": exp, "roles": []string{"viewer"}})
+	expiredToken := signToken(map[string]interface{}{"sub": "jane", "exp": time.Now().Add(-time.Hour).Unix(), "roles": []string{"admin"}})
+
+	newServer := func(us domain.ClientUsecase) *echo.Echo {
+		e := echo.New()
+		e.Use(middleware.JWTAuth(jwt.NewVerifier([]jwt.Key{jwt.NewHMACKey("", jwtSecret)}, jwt.Options{})))
+		e.Use(middleware.APIKeyAuth(us))
+		e.GET("/movies", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		e.DELETE("/logs/:id", func(c echo.Context) error {
+			claims, _ := jwt.FromContext(c.Request().Context())
+			return c.JSON(http.StatusOK, claims.Subject)
+		}, middleware.RequireRole("admin"))
+		return e
+	}
+
+	serve := func(e *echo.Echo, method, path, authorization, key string) *test.ResponseRecorder {
+		req := test.NewRequest(method, path, nil)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		if key != "" {
+			req.Header.Set(middleware.HeaderAPIKey, key)
+		}
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+		return res
+	}
+
+	tokenClient := func(subject string) domain.Client {
+		return domain.Client{Subject: subject, Name: subject, Active: true}
+	}
+
+	t.Run("admin", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("AuthenticateToken", mock.Anything, "jane").Return(tokenClient("jane"), nil).Once()
+		mockClientUCase.On("Allow", mock.Anything, tokenClient("jane")).Return(nil).Once()
+
+		res := serve(newServer(mockClientUCase), echo.DELETE, "/logs/1", "Bearer "+adminToken, "")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `"jane"`, res.Body.String())
+		mockClientUCase.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-role", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("AuthenticateToken", mock.Anything, "john").Return(tokenClient("john"), nil).Once()
+		mockClientUCase.On("Allow", mock.Anything, tokenClient("john")).Return(nil).Once()
+
+		res := serve(newServer(mockClientUCase), echo.DELETE, "/logs/1", "bearer "+viewerToken, "")
+		assert.Equal(t, http.StatusForbidden, res.Code)
+	})
+
+	t.Run("token-without-role", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("AuthenticateToken", mock.Anything, "john").Return(tokenClient("john"), nil).Once()
+		mockClientUCase.On("Allow", mock.Anything, tokenClient("john")).Return(nil).Once()
+
+		res := serve(newServer(mockClientUCase), echo.GET, "/movies", "Bearer "+viewerToken, "")
+		assert.Equal(t, http.StatusOK, res.Code)
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("token-over-quota", func(t *testing.T) {
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("AuthenticateToken", mock.Anything, "john").Return(tokenClient("john"), nil).Twice()
+		mockClientUCase.On("Allow", mock.Anything, tokenClient("john")).Return(nil).Once()
+		mockClientUCase.On("Allow", mock.Anything, tokenClient("john")).Return(&domain.RateLimitError{RetryAfter: time.Hour}).Once()
+		e := newServer(mockClientUCase)
+
+		res := serve(e, echo.GET, "/movies", "Bearer "+viewerToken, "")
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		res = serve(e, echo.GET, "/movies", "Bearer "+viewerToken, "")
+		assert.Equal(t, http.StatusTooManyRequests, res.Code)
+		assert.Equal(t, "3600", res.Header().Get("Retry-After"))
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("token-and-api-key", func(t *testing.T) {
+		mockClient := domain.Client{ID: 7, Active: true}
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("Authenticate", mock.Anything, "secret").Return(mockClient, nil).Once()
+		mockClientUCase.On("Allow", mock.Anything, mockClient).Return(nil).Once()
+
+		res := serve(newServer(mockClientUCase), echo.GET, "/movies", "Bearer "+viewerToken, "secret")
+		assert.Equal(t, http.StatusOK, res.Code)
+		mockClientUCase.AssertNotCalled(t, "AuthenticateToken", mock.Anything, mock.Anything)
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	t.Run("api-key-only", func(t *testing.T) {
+		mockClient := domain.Client{ID: 7, Active: true}
+		mockClientUCase := new(mocks.ClientUsecase)
+		mockClientUCase.On("Authenticate", mock.Anything, "secret").Return(mockClient, nil).Twice()
+		mockClientUCase.On("Allow", mock.Anything, mockClient).Return(nil).Twice()
+		e := newServer(mockClientUCase)
+
+		res := serve(e, echo.GET, "/movies", "", "secret")
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		// an API key alone doesn't grant the admin role
+		res = serve(e, echo.DELETE, "/logs/1", "", "secret")
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, "Bearer", res.Header().Get(echo.HeaderWWWAuthenticate))
+		mockClientUCase.AssertExpectations(t)
+	})
+
+	for name, authorization := range map[string]string{
+		"expired":   "Bearer " + expiredToken,
+		"malformed": "Bearer not-a-token",
+		"tampered":  "Bearer " + adminToken[:len(adminToken)-2] + "xx",
+		"basic":     "Basic dXNlcjpwYXNz",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockClientUCase := new(mocks.ClientUsecase)
+
+			res := serve(newServer(mockClientUCase), echo.GET, "/movies", authorization, "secret")
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+			assert.Equal(t, `Bearer error="invalid_token"`, res.Header().Get(echo.HeaderWWWAuthenticate))
+			assert.JSONEq(t, `{"message":"`+domain.ErrInvalidToken.Error()+`"}`, res.Body.String())
+			mockClientUCase.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/jwt"
+	"github.com/bxcodec/go-clean-arch/logger"
+)
+
+const maxRequestIDLength = 128
+
+// RequestLogger will give every request an id, taken from the X-Request-ID header when the caller sent a usable one,
+// echo it back, store an entry carrying it in the request context and log the request once served
+func RequestLogger(l *logrus.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(requestID) {
+				requestID = newRequestID()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			entry := logrus.NewEntry(l).WithField("request_id", requestID)
+			if sc := trace.SpanContextFromContext(req.Context()); sc.IsValid() {
+				entry = entry.WithField("trace_id", sc.TraceID().String())
+			}
+			c.SetRequest(req.WithContext(logger.NewContext(req.Context(), entry)))
+
+			err := next(c)
+			if err != nil {
+				// let echo write the error now so the logged status and size are the ones sent
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			fields := logrus.Fields{
+				"method":     req.Method,
+				"path":       req.URL.Path,
+				"route":      c.Path(),
+				"status":     status,
+				"latency_ms": float64(time.Since(start)) / float64(time.Millisecond),
+				"bytes_in":   req.ContentLength,
+				"bytes_out":  c.Response().Size,
+				"remote_ip":  c.RealIP(),
+				"user_agent": req.UserAgent(),
+			}
+			if err != nil {
+				fields["error"] = err.Error()
+			}
+			if client, ok := domain.ClientFromContext(c.Request().Context()); ok {
+				fields["client_id"] = client.ID
+			}
+			if claims, ok := jwt.FromContext(c.Request().Context()); ok {
+				fields["subject"] = claims.Subject
+			}
+
+			e := entry.WithFields(fields)
+			switch {
+			case status >= http.StatusInternalServerError:
+				e.Error("request served")
+			case status >= http.StatusBadRequest:
+				e.Warn("request served")
+			default:
+				e.Info("request served")
+			}
+			return nil
+		}
+	}
+}
+
+// validRequestID keeps caller ids short and printable so they cannot forge log lines
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bxcodec/go-clean-arch/metrics"
+)
+
+// Metrics will count and time every request by route and status code
+func Metrics(reg prometheus.Registerer) (echo.MiddlewareFunc, error) {
+	requests, err := metrics.NewCounterVec(reg, "http_requests_total",
+		"HTTP requests served by method, route and status code.", "method", "route", "code")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := metrics.NewHistogramVec(reg, "http_request_duration_seconds",
+		"Latency of the HTTP requests by method and route.", "method", "route")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			status := c.Response().Status
+			if err != nil {
+				status = http.StatusInternalServerError
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				}
+			}
+
+			route := routeOf(c)
+
+			method := c.Request().Method
+			duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+			requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+			return err
+		}
+	}, nil
+}
+
+// routeOf will return the pattern of the matched route so the cardinality stays low,
+// echo reports the raw path of unmatched requests so those share a single label instead
+func routeOf(c echo.Context) string {
+	path := c.Path()
+	for _, r := range c.Echo().Routes() {
+		if r.Path == path {
+			return path
+		}
+	}
+	return "unmatched"
+}
@@ -1,21 +1,177 @@
 package middleware
 
-import "github.com/labstack/echo"
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// CORSConfig represent the cross origin policy applied by GoMiddleware.CORS
+type CORSConfig struct {
+	// AllowOrigins lists the origins allowed to call the API, "*" allows any origin and a single "*"
+	// inside an entry matches any non empty part such as https://*.example.com
+	AllowOrigins []string
+	// AllowMethods answered to preflight requests, DefaultCORSConfig.AllowMethods when empty
+	AllowMethods []string
+	// AllowHeaders answered to preflight requests, the requested headers are reflected when empty
+	AllowHeaders []string
+	// ExposeHeaders lists the response headers readable by the browser
+	ExposeHeaders []string
+	// MaxAge tells the browser how long a preflight answer can be cached, not sent when zero
+	MaxAge time.Duration
+	// AllowCredentials lets the browser send cookies and authorization headers, it can't be combined with
+	// the "*" origin
+	AllowCredentials bool
+}
+
+// ErrAnyOriginWithCredentials is returned by InitMiddleware when credentials are allowed from any origin,
+// which would let every site make authenticated calls on behalf of the user
+var ErrAnyOriginWithCredentials = errors.New("cors: allow_credentials can't be used with the \"*\" origin")
+
+// DefaultCORSConfig is the policy used for the fields left empty
+var DefaultCORSConfig = CORSConfig{
+	AllowOrigins: []string{"*"},
+	AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
+}
 
 // GoMiddleware represent the data-struct for middleware
 type GoMiddleware struct {
-	// another stuff , may be needed by middleware
+	cors           CORSConfig
+	allowMethods   string
+	allowHeaders   string
+	exposeHeaders  string
+	maxAge         string
+	anyOrigin      bool
+	originPatterns []originPattern
 }
 
-// CORS will handle the CORS middleware
+// CORS will handle the CORS middleware, answering preflight requests itself and adding the CORS headers
+// to the other responses of an allowed origin
 func (m *GoMiddleware) CORS(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		c.Response().Header().Set("Access-Control-Allow-Origin", "*")
-		return next(c)
+		req := c.Request()
+		header := c.Response().Header()
+		origin := req.Header.Get(echo.HeaderOrigin)
+		preflight := req.Method == echo.OPTIONS && req.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+
+		header.Add(echo.HeaderVary, echo.HeaderOrigin)
+		if preflight {
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+		}
+
+		allowOrigin, ok := m.allowOrigin(origin)
+		if !ok {
+			if preflight {
+				// without any CORS header the browser refuses the actual request
+				return c.NoContent(http.StatusNoContent)
+			}
+			return next(c)
+		}
+
+		header.Set(echo.HeaderAccessControlAllowOrigin, allowOrigin)
+		if m.cors.AllowCredentials {
+			header.Set(echo.HeaderAccessControlAllowCredentials, "true")
+		}
+
+		if !preflight {
+			if m.exposeHeaders != "" {
+				header.Set(echo.HeaderAccessControlExposeHeaders, m.exposeHeaders)
+			}
+			return next(c)
+		}
+
+		header.Set(echo.HeaderAccessControlAllowMethods, m.allowMethods)
+		allowHeaders := m.allowHeaders
+		if allowHeaders == "" {
+			allowHeaders = req.Header.Get(echo.HeaderAccessControlRequestHeaders)
+		}
+		if allowHeaders != "" {
+			header.Set(echo.HeaderAccessControlAllowHeaders, allowHeaders)
+		}
+		if m.maxAge != "" {
+			header.Set(echo.HeaderAccessControlMaxAge, m.maxAge)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// allowOrigin return the value of Access-Control-Allow-Origin for the request origin, false when the origin
+// is not allowed or the request is not a cross origin one
+func (m *GoMiddleware) allowOrigin(origin string) (string, bool) {
+	if origin == "" {
+		return "", false
+	}
+	if m.anyOrigin {
+		return "*", true
+	}
+
+	for _, p := range m.originPatterns {
+		if p.match(origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// originPattern represent an allowed origin, with an optional single wildcard between prefix and suffix
+type originPattern struct {
+	prefix   string
+	suffix   string
+	wildcard bool
+}
+
+func newOriginPattern(origin string) originPattern {
+	origin = strings.ToLower(origin)
+	i := strings.IndexByte(origin, '*')
+	if i < 0 {
+		return originPattern{prefix: origin}
+	}
+	return originPattern{prefix: origin[:i], suffix: origin[i+1:], wildcard: true}
+}
+
+func (p originPattern) match(origin string) bool {
+	origin = strings.ToLower(origin)
+	if !p.wildcard {
+		return origin == p.prefix
 	}
+	return len(origin) > len(p.prefix)+len(p.suffix) &&
+		strings.HasPrefix(origin, p.prefix) &&
+		strings.HasSuffix(origin, p.suffix)
 }
 
-// InitMiddleware initialize the middleware
-func InitMiddleware() *GoMiddleware {
-	return &GoMiddleware{}
+// InitMiddleware initialize the middleware, the empty fields of cors are taken from DefaultCORSConfig.
+// It returns ErrAnyOriginWithCredentials when credentials are allowed from any origin
+func InitMiddleware(cors CORSConfig) (*GoMiddleware, error) {
+	if len(cors.AllowOrigins) == 0 {
+		cors.AllowOrigins = DefaultCORSConfig.AllowOrigins
+	}
+	if len(cors.AllowMethods) == 0 {
+		cors.AllowMethods = DefaultCORSConfig.AllowMethods
+	}
+
+	m := &GoMiddleware{
+		cors:          cors,
+		allowMethods:  strings.Join(cors.AllowMethods, ","),
+		allowHeaders:  strings.Join(cors.AllowHeaders, ","),
+		exposeHeaders: strings.Join(cors.ExposeHeaders, ","),
+	}
+	if cors.MaxAge > 0 {
+		m.maxAge = strconv.Itoa(int(cors.MaxAge / time.Second))
+	}
+	for _, origin := range cors.AllowOrigins {
+		if origin == "*" {
+			m.anyOrigin = true
+			continue
+		}
+		m.originPatterns = append(m.originPatterns, newOriginPattern(origin))
+	}
+	if m.anyOrigin && cors.AllowCredentials {
+		return nil, ErrAnyOriginWithCredentials
+	}
+	return m, nil
 }
@@ -1,29 +1,329 @@
 package middleware_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
 	test "net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/bxcodec/go-clean-arch/logger"
 	"github.com/bxcodec/go-clean-arch/movie/delivery/http/middleware"
+	"github.com/bxcodec/go-clean-arch/tracing"
 )
 
 func TestCORS(t *testing.T) {
 	e := echo.New()
 	req := test.NewRequest(echo.GET, "/", nil)
+	req.Header.Set(echo.HeaderOrigin, "http://localhost:3000")
 	res := test.NewRecorder()
 	c := e.NewContext(req, res)
-	m := middleware.InitMiddleware()
+	m, err := middleware.InitMiddleware(middleware.CORSConfig{})
+	require.NoError(t, err)
 
 	h := m.CORS(echo.HandlerFunc(func(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
 	}))
 
-	err := h(c)
+	err = h(c)
 	require.NoError(t, err)
 	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
 }
+
+func TestCORSPolicy(t *testing.T) {
+	cfg := middleware.CORSConfig{
+		AllowOrigins:     []string{"https://movies.example.com", "https://*.staging.example.com"},
+		AllowMethods:     []string{echo.GET, echo.DELETE},
+		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		ExposeHeaders:    []string{"X-Cursor", "X-Request-ID"},
+		MaxAge:           10 * time.Minute,
+		AllowCredentials: true,
+	}
+
+	newServer := func(cfg middleware.CORSConfig) (*echo.Echo, *int) {
+		calls := new(int)
+		m, err := middleware.InitMiddleware(cfg)
+		require.NoError(t, err)
+		e := echo.New()
+		e.Use(m.CORS)
+		e.GET("/movies", func(c echo.Context) error {
+			*calls++
+			c.Response().Header().Set("X-Cursor", "next")
+			return c.JSON(http.StatusOK, "ok")
+		})
+		return e, calls
+	}
+
+	t.Run("allowed-origin", func(t *testing.T) {
+		e, calls := newServer(cfg)
+		req := test.NewRequest(echo.GET, "/movies", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://movies.example.com")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, "https://movies.example.com", res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "true", res.Header().Get(echo.HeaderAccessControlAllowCredentials))
+		assert.Equal(t, "X-Cursor,X-Request-ID", res.Header().Get(echo.HeaderAccessControlExposeHeaders))
+		assert.Equal(t, []string{echo.HeaderOrigin}, res.Header()[echo.HeaderVary])
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowMethods))
+	})
+
+	t.Run("wildcard-origin", func(t *testing.T) {
+		e, _ := newServer(cfg)
+		for origin, allowed := range map[string]bool{
+			"https://pr-42.staging.example.com": true,
+			"https://PR-42.Staging.Example.com": true,
+			"https://.staging.example.com":      false,
+			"https://staging.example.com":       false,
+			"http://pr-42.staging.example.com":  false,
+			"https://evil.com/.staging.example": false,
+		} {
+			req := test.NewRequest(echo.GET, "/movies", nil)
+			req.Header.Set(echo.HeaderOrigin, origin)
+			res := test.NewRecorder()
+			e.ServeHTTP(res, req)
+
+			if allowed {
+				assert.Equal(t, origin, res.Header().Get(echo.HeaderAccessControlAllowOrigin), origin)
+			} else {
+				assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowOrigin), origin)
+			}
+		}
+	})
+
+	t.Run("disallowed-origin", func(t *testing.T) {
+		e, calls := newServer(cfg)
+		req := test.NewRequest(echo.GET, "/movies", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://evil.com")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+
+		// the request is still served, the browser hides the response from the page
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 1, *calls)
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowCredentials))
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlExposeHeaders))
+	})
+
+	t.Run("same-origin", func(t *testing.T) {
+		e, calls := newServer(cfg)
+		res := test.NewRecorder()
+		e.ServeHTTP(res, test.NewRequest(echo.GET, "/movies", nil))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 1, *calls)
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		e, calls := newServer(cfg)
+		req := test.NewRequest(echo.OPTIONS, "/movies", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://movies.example.com")
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, echo.GET)
+		req.Header.Set(echo.HeaderAccessControlRequestHeaders, "authorization")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, 0, *calls)
+		assert.Equal(t, "https://movies.example.com", res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "true", res.Header().Get(echo.HeaderAccessControlAllowCredentials))
+		assert.Equal(t, "GET,DELETE", res.Header().Get(echo.HeaderAccessControlAllowMethods))
+		assert.Equal(t, "Authorization,Content-Type", res.Header().Get(echo.HeaderAccessControlAllowHeaders))
+		assert.Equal(t, "600", res.Header().Get(echo.HeaderAccessControlMaxAge))
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlExposeHeaders))
+		assert.Equal(t, []string{
+			echo.HeaderOrigin,
+			echo.HeaderAccessControlRequestMethod,
+			echo.HeaderAccessControlRequestHeaders,
+		}, res.Header()[echo.HeaderVary])
+	})
+
+	t.Run("preflight-disallowed-origin", func(t *testing.T) {
+		e, calls := newServer(cfg)
+		req := test.NewRequest(echo.OPTIONS, "/movies", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://evil.com")
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, echo.DELETE)
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, 0, *calls)
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowMethods))
+	})
+
+	t.Run("preflight-reflects-headers", func(t *testing.T) {
+		e, _ := newServer(middleware.CORSConfig{})
+		req := test.NewRequest(echo.OPTIONS, "/movies", nil)
+		req.Header.Set(echo.HeaderOrigin, "http://localhost:3000")
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, echo.POST)
+		req.Header.Set(echo.HeaderAccessControlRequestHeaders, "X-Request-ID")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, "*", res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlAllowCredentials))
+		assert.Equal(t, "GET,HEAD,PUT,PATCH,POST,DELETE", res.Header().Get(echo.HeaderAccessControlAllowMethods))
+		assert.Equal(t, "X-Request-ID", res.Header().Get(echo.HeaderAccessControlAllowHeaders))
+		assert.Empty(t, res.Header().Get(echo.HeaderAccessControlMaxAge))
+	})
+
+	t.Run("options-without-preflight", func(t *testing.T) {
+		e, _ := newServer(cfg)
+		req := test.NewRequest(echo.OPTIONS, "/movies", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://movies.example.com")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+
+		// not a preflight, the router answers as for any other unsupported method
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+		assert.Equal(t, "https://movies.example.com", res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("any-origin-with-credentials", func(t *testing.T) {
+		for _, origins := range [][]string{{"*"}, {}, {"https://movies.example.com", "*"}} {
+			m, err := middleware.InitMiddleware(middleware.CORSConfig{AllowOrigins: origins, AllowCredentials: true})
+			assert.Equal(t, middleware.ErrAnyOriginWithCredentials, err, "%v", origins)
+			assert.Nil(t, m)
+		}
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	e := echo.New()
+	m, err := middleware.Metrics(reg)
+	require.NoError(t, err)
+	e.Use(m)
+	e.GET("/movies/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	for _, path := range []string{"/movies/tt0111161", "/movies/tt0372784", "/unknown"} {
+		res := test.NewRecorder()
+		e.ServeHTTP(res, test.NewRequest(echo.GET, path, nil))
+	}
+
+	rec := test.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(rec, test.NewRequest(http.MethodGet, "/metrics", nil))
+	buf := rec.Body
+	assert.Contains(t, buf.String(), `http_requests_total{code="200",method="GET",route="/movies/:id"} 2`)
+	assert.Contains(t, buf.String(), `http_requests_total{code="404",method="GET",route="unmatched"} 1`)
+	assert.Contains(t, buf.String(), `http_request_duration_seconds_count{method="GET",route="/movies/:id"} 2`)
+}
+
+func TestTracing(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	e := echo.New()
+	e.Use(middleware.Tracing(tp))
+	e.GET("/movies/:id", func(c echo.Context) error {
+		_, span := tracing.Start(c.Request().Context(), "movieUsecase.GetByID")
+		span.End()
+		return c.JSON(http.StatusServiceUnavailable, "unavailable")
+	})
+
+	req := test.NewRequest(echo.GET, "/movies/tt0111161", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(test.NewRecorder(), req)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "GET /movies/:id", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+	assert.Contains(t, server.Attributes(), attribute.Int("http.status_code", http.StatusServiceUnavailable))
+	assert.Equal(t, codes.Error, server.Status().Code)
+}
+
+func TestRequestLogger(t *testing.T) {
+	newLogger := func() (*logrus.Logger, *bytes.Buffer) {
+		var buf bytes.Buffer
+		l := logrus.New()
+		l.Out = &buf
+		l.Formatter = &logrus.JSONFormatter{}
+		return l, &buf
+	}
+
+	t.Run("propagate", func(t *testing.T) {
+		l, buf := newLogger()
+		e := echo.New()
+		e.Use(middleware.RequestLogger(l))
+		e.GET("/movies/:id", func(c echo.Context) error {
+			logger.FromContext(c.Request().Context()).Warn("movie log queue is full")
+			return c.JSON(http.StatusOK, "ok")
+		})
+
+		req := test.NewRequest(echo.GET, "/movies/tt0111161", nil)
+		req.Header.Set(echo.HeaderXRequestID, "req-42")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+		assert.Equal(t, "req-42", res.Header().Get(echo.HeaderXRequestID))
+
+		dec := json.NewDecoder(buf)
+		var inner, access map[string]interface{}
+		require.NoError(t, dec.Decode(&inner))
+		require.NoError(t, dec.Decode(&access))
+		assert.Equal(t, "req-42", inner["request_id"])
+		assert.Equal(t, "movie log queue is full", inner["msg"])
+
+		assert.Equal(t, "req-42", access["request_id"])
+		assert.Equal(t, "GET", access["method"])
+		assert.Equal(t, "/movies/tt0111161", access["path"])
+		assert.Equal(t, "/movies/:id", access["route"])
+		assert.Equal(t, float64(http.StatusOK), access["status"])
+		assert.Equal(t, "info", access["level"])
+		assert.Contains(t, access, "latency_ms")
+		assert.Equal(t, float64(res.Body.Len()), access["bytes_out"])
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		l, buf := newLogger()
+		e := echo.New()
+		e.Use(middleware.RequestLogger(l))
+		e.GET("/movies/:id", func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusNotFound, "Your requested movie is not found")
+		})
+
+		req := test.NewRequest(echo.GET, "/movies/tt0000000", nil)
+		req.Header.Set(echo.HeaderXRequestID, "bad id\nlevel=error")
+		res := test.NewRecorder()
+		e.ServeHTTP(res, req)
+
+		requestID := res.Header().Get(echo.HeaderXRequestID)
+		assert.Len(t, requestID, 32)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		var access map[string]interface{}
+		require.NoError(t, json.NewDecoder(buf).Decode(&access))
+		assert.Equal(t, requestID, access["request_id"])
+		assert.Equal(t, float64(http.StatusNotFound), access["status"])
+		assert.Equal(t, "warning", access["level"])
+		assert.Contains(t, access["error"], "Your requested movie is not found")
+	})
+}
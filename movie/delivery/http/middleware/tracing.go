@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+// Tracing will start a server span for every request, continuing the trace of the caller when it sends a traceparent header.
+// The span travels in the request context down to the usecases and repositories
+func Tracing(tp trace.TracerProvider) echo.MiddlewareFunc {
+	tracer := tp.Tracer("github.com/bxcodec/go-clean-arch/movie/delivery/http/middleware")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := tracing.Extract(req.Context(), req.Header)
+
+			route := routeOf(c)
+			ctx, span := tracer.Start(ctx, req.Method+" "+route, trace.WithSpanKind(trace.SpanKindServer))
+			defer span.End()
+			span.SetAttributes(
+				attribute.String("http.method", req.Method),
+				attribute.String("http.route", route),
+				attribute.String("http.target", req.URL.RequestURI()),
+			)
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			status := c.Response().Status
+			if err != nil {
+				status = http.StatusInternalServerError
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				}
+			}
+			span.SetAttributes(attribute.Int("http.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return err
+		}
+	}
+}
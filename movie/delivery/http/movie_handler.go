@@ -1,76 +1,132 @@
 package http
 
 import (
+	"errors"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 
 	"github.com/labstack/echo"
-	"github.com/sirupsen/logrus"
 
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
 )
 
 // ResponseError represent the reseponse error struct
 type ResponseError struct {
-	Message string `json:"message"`
+	Message string              `json:"message"`
+	Errors  []domain.FieldError `json:"errors,omitempty"`
 }
 
 // MovieHandler  represent the httphandler for movie
 type MovieHandler struct {
-	MUsecase domain.MovieUsecase
-	LogRepo  domain.LogmovieRepository
+	MUsecase  domain.MovieUsecase
+	LogRepo   domain.LogmovieRepository
+	Normalize domain.MovieNormalizer
+	// Catalog reports whether the local catalog is wired, the source of a search can't be chosen without it
+	Catalog bool
 }
 
-// NewMovieHandler will initialize the movies/ resources endpoint
-func NewMovieHandler(e *echo.Echo, us domain.MovieUsecase, lr domain.LogmovieRepository) {
+// NewMovieHandler will initialize the movies/ resources endpoint, the /v2 routes serving the movies as normalized
+// by normalize. Searching is public, the lookups by id are authenticated by the given middlewares
+func NewMovieHandler(e *echo.Echo, us domain.MovieUsecase, lr domain.LogmovieRepository, normalize domain.MovieNormalizer, catalog bool, auth ...echo.MiddlewareFunc) {
 	handler := &MovieHandler{
-		MUsecase: us,
-		LogRepo:  lr,
+		MUsecase:  us,
+		LogRepo:   lr,
+		Normalize: normalize,
+		Catalog:   catalog,
 	}
 	e.GET("/movies", handler.FetchMovie)
-	e.GET("/movies/:id", handler.GetByID)
+	e.GET("/movies/:id", handler.GetByID, auth...)
+
+	v2 := e.Group("/v2")
+	v2.GET("/movies", handler.FetchMovieV2)
+	v2.GET("/movies/:id", handler.GetByIDV2, auth...)
 }
 
 // FetchMovie will fetch the movie based on given params
 func (a *MovieHandler) FetchMovie(c echo.Context) error {
-	searchword := c.QueryParam("searchword")
-	page := c.QueryParam("paginatioon")
-	ctx := c.Request().Context()
-
-	listAr, _, err := a.MUsecase.Fetch(ctx, page, searchword)
+	page, err := a.fetch(c)
 	if err != nil {
-		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return errorResponse(c, err)
 	}
-
-	return c.JSON(http.StatusOK, listAr)
+	return c.JSON(http.StatusOK, page.Movies)
 }
 
 // GetByID will get movie by given id
 func (a *MovieHandler) GetByID(c echo.Context) error {
-	id := c.Param("id")
+	art, err := a.getByID(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, art)
+}
+
+// fetch will search the movies and set the pagination headers shared by every version of the API
+func (a *MovieHandler) fetch(c echo.Context) (domain.MoviePage, error) {
+	req := newFetchMovieRequest(c)
+	if err := validateRequest(req); err != nil {
+		return domain.MoviePage{}, err
+	}
+	if req.Source != "" && !a.Catalog {
+		return domain.MoviePage{}, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field:   "source",
+			Message: "is not available, the local catalog is disabled",
+		}}}
+	}
 
+	page, err := a.MUsecase.Fetch(c.Request().Context(), req.criteria())
+	if err != nil {
+		return domain.MoviePage{}, err
+	}
+
+	c.Response().Header().Set(`X-Cursor`, page.NextCursor)
+	c.Response().Header().Set(`X-Prev-Cursor`, page.PrevCursor)
+	c.Response().Header().Set(`X-Total-Count`, strconv.Itoa(page.Total))
+	return page, nil
+}
+
+// getByID will get the movie and log the lookup, a failing log doesn't fail the request
+func (a *MovieHandler) getByID(c echo.Context) (domain.Movies, error) {
+	req := newGetMovieRequest(c)
+	if err := validateRequest(req); err != nil {
+		return domain.Movies{}, err
+	}
 	ctx := c.Request().Context()
 
-	art, err := a.MUsecase.GetByID(ctx, id)
+	art, err := a.MUsecase.GetByID(ctx, req.ID, domain.PlotLength(req.Plot))
 	if err != nil {
-		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return domain.Movies{}, err
 	}
 
-	var wg sync.WaitGroup
+	err = a.LogRepo.Store(ctx, &art)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("log movie %s: %s", art.ID, err)
+	}
+	return art, nil
+}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		err = a.LogRepo.Store(ctx, &art)
-		if err != nil {
-			logrus.Warnf(err.Error())
+// errorResponse will write the error with its matching status code, telling rate limited clients when to retry
+// and invalid requests which fields to fix
+func errorResponse(c echo.Context, err error) error {
+	logger.FromContext(c.Request().Context()).Error(err)
 
+	var rateLimitErr *domain.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		retryAfter := int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
 		}
-	}()
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
 
-	wg.Wait()
+	res := ResponseError{Message: err.Error()}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		res.Errors = validationErr.Fields
+	}
 
-	return c.JSON(http.StatusOK, art)
+	return c.JSON(getStatusCode(err), res)
 }
 
 func getStatusCode(err error) int {
@@ -78,14 +134,21 @@ func getStatusCode(err error) int {
 		return http.StatusOK
 	}
 
-	logrus.Error(err)
-	switch err {
-	case domain.ErrInternalServerError:
+	switch {
+	case errors.Is(err, domain.ErrInternalServerError):
 		return http.StatusInternalServerError
-	case domain.ErrNotFound:
+	case errors.Is(err, domain.ErrNotFound):
 		return http.StatusNotFound
-	case domain.ErrConflict:
+	case errors.Is(err, domain.ErrConflict):
 		return http.StatusConflict
+	case errors.Is(err, domain.ErrBadParamInput), errors.Is(err, domain.ErrTooManyResults):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return http.StatusBadGateway
+	case errors.Is(err, domain.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, domain.ErrServiceUnavailable):
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
@@ -2,6 +2,7 @@ package http_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -17,51 +18,58 @@ import (
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	movieHttp "github.com/bxcodec/go-clean-arch/movie/delivery/http"
+	movieRepo "github.com/bxcodec/go-clean-arch/movie/repository/movie"
 )
 
 func TestFetch(t *testing.T) {
-	var mockMovie domain.Movie
+	var mockMovie domain.Movies
 	err := faker.FakeData(&mockMovie)
 	assert.NoError(t, err)
 	mockUCase := new(mocks.MovieUsecase)
-	mockListMovie := make([]domain.Movie, 0)
+	mockListMovie := make([]domain.Movies, 0)
 	mockListMovie = append(mockListMovie, mockMovie)
-	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListMovie, "10", nil)
+	mockPage := domain.MoviePage{
+		Movies:     mockListMovie,
+		Total:      42,
+		NextCursor: "3",
+		PrevCursor: "1",
+	}
+	mockUCase.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman", Cursor: cursor}).Return(mockPage, nil)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/movie?num=1&cursor="+cursor, strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/movies?searchword=Batman&cursor="+cursor, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	handler := movieHttp.MovieHandler{
-		AUsecase: mockUCase,
+		MUsecase: mockUCase,
 	}
 	err = handler.FetchMovie(c)
 	require.NoError(t, err)
 
-	responseCursor := rec.Header().Get("X-Cursor")
-	assert.Equal(t, "10", responseCursor)
+	assert.Equal(t, "3", rec.Header().Get("X-Cursor"))
+	assert.Equal(t, "1", rec.Header().Get("X-Prev-Cursor"))
+	assert.Equal(t, "42", rec.Header().Get("X-Total-Count"))
 	assert.Equal(t, http.StatusOK, rec.Code)
 	mockUCase.AssertExpectations(t)
 }
 
 func TestFetchError(t *testing.T) {
 	mockUCase := new(mocks.MovieUsecase)
-	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(nil, "", domain.ErrInternalServerError)
+	mockUCase.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman", Cursor: cursor}).Return(domain.MoviePage{}, domain.ErrInternalServerError)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/movie?num=1&cursor="+cursor, strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/movies?searchword=Batman&cursor="+cursor, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	handler := movieHttp.MovieHandler{
-		AUsecase: mockUCase,
+		MUsecase: mockUCase,
 	}
 	err = handler.FetchMovie(c)
 	require.NoError(t, err)
@@ -73,98 +81,367 @@ func TestFetchError(t *testing.T) {
 }
 
 func TestGetByID(t *testing.T) {
-	var mockMovie domain.Movie
+	var mockMovie domain.Movies
 	err := faker.FakeData(&mockMovie)
 	assert.NoError(t, err)
 
+	mockMovie.ID = "tt0372784"
+
 	mockUCase := new(mocks.MovieUsecase)
+	mockLogRepo := new(mocks.LogmovieRepository)
 
-	num := int(mockMovie.ID)
+	id := mockMovie.ID
 
-	mockUCase.On("GetByID", mock.Anything, int64(num)).Return(mockMovie, nil)
+	mockUCase.On("GetByID", mock.Anything, id, domain.PlotLength("")).Return(mockMovie, nil)
+	mockLogRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Movies")).Return(nil)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/movie/"+strconv.Itoa(num), strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/movies/"+id, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	c.SetPath("movie/:id")
+	c.SetPath("movies/:id")
 	c.SetParamNames("id")
-	c.SetParamValues(strconv.Itoa(num))
+	c.SetParamValues(id)
 	handler := movieHttp.MovieHandler{
-		AUsecase: mockUCase,
+		MUsecase: mockUCase,
+		LogRepo:  mockLogRepo,
 	}
 	err = handler.GetByID(c)
 	require.NoError(t, err)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 	mockUCase.AssertExpectations(t)
+	mockLogRepo.AssertExpectations(t)
 }
 
-func TestStore(t *testing.T) {
-	mockMovie := domain.Movie{
-		Title:     "Title",
-		Content:   "Content",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	tempMockMovie := mockMovie
-	tempMockMovie.ID = 0
+func TestGetByIDNotFound(t *testing.T) {
 	mockUCase := new(mocks.MovieUsecase)
+	mockLogRepo := new(mocks.LogmovieRepository)
 
-	j, err := json.Marshal(tempMockMovie)
-	assert.NoError(t, err)
-
-	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Movie")).Return(nil)
+	mockUCase.On("GetByID", mock.Anything, "tt0000000", domain.PlotLength("")).Return(domain.Movies{}, domain.ErrNotFound)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.POST, "/movie", strings.NewReader(string(j)))
+	req, err := http.NewRequest(echo.GET, "/movies/tt0000000", strings.NewReader(""))
 	assert.NoError(t, err)
-	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	c.SetPath("/movie")
-
+	c.SetPath("movies/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("tt0000000")
 	handler := movieHttp.MovieHandler{
-		AUsecase: mockUCase,
+		MUsecase: mockUCase,
+		LogRepo:  mockLogRepo,
 	}
-	err = handler.Store(c)
+	err = handler.GetByID(c)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
 	mockUCase.AssertExpectations(t)
+	mockLogRepo.AssertExpectations(t)
 }
 
-func TestDelete(t *testing.T) {
-	var mockMovie domain.Movie
-	err := faker.FakeData(&mockMovie)
-	assert.NoError(t, err)
-
-	mockUCase := new(mocks.MovieUsecase)
+func TestGetByIDErrorStatus(t *testing.T) {
+	tests := map[error]int{
+		domain.ErrNotFound:            http.StatusNotFound,
+		domain.ErrInvalidAPIKey:       http.StatusBadGateway,
+		domain.ErrRateLimited:         http.StatusTooManyRequests,
+		domain.ErrTooManyResults:      http.StatusBadRequest,
+		domain.ErrBadParamInput:       http.StatusBadRequest,
+		domain.ErrServiceUnavailable:  http.StatusServiceUnavailable,
+		domain.ErrInternalServerError: http.StatusInternalServerError,
+	}
 
-	num := int(mockMovie.ID)
+	for ucaseErr, status := range tests {
+		mockUCase := new(mocks.MovieUsecase)
+		mockUCase.On("GetByID", mock.Anything, "tt0111161", domain.PlotLength("")).Return(domain.Movies{}, ucaseErr)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/movies/tt0111161", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("movies/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("tt0111161")
+		handler := movieHttp.MovieHandler{
+			MUsecase: mockUCase,
+		}
+		err = handler.GetByID(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, status, rec.Code, ucaseErr.Error())
+		mockUCase.AssertExpectations(t)
+	}
+}
 
-	mockUCase.On("Delete", mock.Anything, int64(num)).Return(nil)
+func TestFetchRateLimited(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+	rateLimitErr := &domain.RateLimitError{RetryAfter: 1500 * time.Millisecond}
+	mockUCase.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(domain.MoviePage{}, rateLimitErr)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.DELETE, "/movie/"+strconv.Itoa(num), strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/movies?searchword=Batman", strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	c.SetPath("movie/:id")
-	c.SetParamNames("id")
-	c.SetParamValues(strconv.Itoa(num))
 	handler := movieHttp.MovieHandler{
-		AUsecase: mockUCase,
+		MUsecase: mockUCase,
+	}
+	err = handler.FetchMovie(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchFilters(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+	criteria := domain.SearchCriteria{Searchword: "Batman", Year: 2005, Type: domain.MovieTypeMovie, Source: domain.SearchSourceLocal}
+	mockUCase.On("Fetch", mock.Anything, criteria).Return(domain.MoviePage{}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/movies?searchword=Batman&year=2005&type=Movie&source=local", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := movieHttp.MovieHandler{
+		MUsecase: mockUCase,
+		Catalog:  true,
 	}
-	err = handler.Delete(c)
+	err := handler.FetchMovie(c)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchSourceWithoutCatalog(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+
+	e := echo.New()
+	movieHttp.NewMovieHandler(e, mockUCase, nil, movieRepo.Normalize, false)
+	req := httptest.NewRequest(echo.GET, "/movies?searchword=Batman&source=local", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"message": "Given Param is not valid",
+		"errors": [{"field": "source", "message": "is not available, the local catalog is disabled"}]
+	}`, rec.Body.String())
+	mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+}
+
+func TestGetByIDPlot(t *testing.T) {
+	mockMovie := domain.Movies{ID: "tt0111161", Title: "The Shawshank Redemption"}
+	mockUCase := new(mocks.MovieUsecase)
+	mockUCase.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotShort).Return(mockMovie, nil)
+	mockLogRepo := new(mocks.LogmovieRepository)
+	mockLogRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Movies")).Return(nil)
+
+	for plot, status := range map[string]int{"short": http.StatusOK, "medium": http.StatusBadRequest} {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/movies/tt0111161?plot="+plot, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("movies/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMovie.ID)
+		handler := movieHttp.MovieHandler{
+			MUsecase: mockUCase,
+			LogRepo:  mockLogRepo,
+		}
+		err := handler.GetByID(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, status, rec.Code, plot)
+	}
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchValidation(t *testing.T) {
+	yearMessage := fmt.Sprintf("must be a year between 1888 and %d", time.Now().Year()+5)
+	tests := map[string][]domain.FieldError{
+		"/movies":                   {{Field: "searchword", Message: "is required"}},
+		"/movies?searchword=%20%20": {{Field: "searchword", Message: "is required"}},
+		"/movies?searchword=a":      {{Field: "searchword", Message: "must be at least 2 characters long"}},
+		"/movies?searchword=" + strings.Repeat("a", 101):                      {{Field: "searchword", Message: "must be at most 100 characters long"}},
+		"/movies?searchword=Batman&year=05":                                   {{Field: "year", Message: yearMessage}},
+		"/movies?searchword=Batman&year=%2B199":                               {{Field: "year", Message: yearMessage}},
+		"/movies?searchword=Batman&year=-199":                                 {{Field: "year", Message: yearMessage}},
+		"/movies?searchword=Batman&year=1887":                                 {{Field: "year", Message: yearMessage}},
+		"/movies?searchword=Batman&year=" + strconv.Itoa(time.Now().Year()+6): {{Field: "year", Message: yearMessage}},
+		"/movies?searchword=Batman&type=game":                                 {{Field: "type", Message: "must be one of movie, series, episode"}},
+		"/movies?searchword=Batman&source=cache":                              {{Field: "source", Message: "must be one of local, remote, auto"}},
+		"/movies?year=abcd&type=game": {
+			{Field: "searchword", Message: "is required"},
+			{Field: "year", Message: yearMessage},
+			{Field: "type", Message: "must be one of movie, series, episode"},
+		},
+	}
+
+	for target, fields := range tests {
+		mockUCase := new(mocks.MovieUsecase)
+
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, target, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := movieHttp.MovieHandler{
+			MUsecase: mockUCase,
+		}
+		err := handler.FetchMovie(c)
+		require.NoError(t, err)
+
+		var res movieHttp.ResponseError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		assert.Equal(t, domain.ErrBadParamInput.Error(), res.Message, target)
+		assert.Equal(t, fields, res.Errors, target)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	}
+}
+
+func TestGetByIDValidation(t *testing.T) {
+	for _, id := range []string{"0111161", "tt011116", "tt0111161x", "nm0000288", "tt0111161;DROP"} {
+		mockUCase := new(mocks.MovieUsecase)
+
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/movies/x", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("movies/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		handler := movieHttp.MovieHandler{
+			MUsecase: mockUCase,
+		}
+		err := handler.GetByID(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, id)
+		assert.JSONEq(t, `{"message":"Given Param is not valid","errors":[{"field":"id","message":"must be an IMDb id such as tt0111161"}]}`, rec.Body.String(), id)
+		mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+var shawshank = domain.Movies{
+	ID:         "tt0111161",
+	Title:      "The Shawshank Redemption",
+	Year:       "1994",
+	Runtime:    "142 min",
+	Genre:      "Drama",
+	Actors:     "Tim Robbins, Morgan Freeman",
+	ImdbRating: "9.3",
+	DVD:        "N/A",
+	Released:   "14 Oct 1994",
+}
+
+func TestGetByIDLegacyShape(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+	mockLogRepo := new(mocks.LogmovieRepository)
+	mockUCase.On("GetByID", mock.Anything, shawshank.ID, domain.PlotLength("")).Return(shawshank, nil)
+	mockLogRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Movies")).Return(nil)
+
+	e := echo.New()
+	movieHttp.NewMovieHandler(e, mockUCase, mockLogRepo, movieRepo.Normalize, false)
+	req := httptest.NewRequest(echo.GET, "/movies/"+shawshank.ID, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, "142 min", res["Runtime"])
+	assert.Equal(t, "14 Oct 1994", res["created_at"])
+	assert.Equal(t, "9.3", res["imdbRating"])
+	assert.NotContains(t, res, "Released")
+	assert.NotContains(t, res, "DVD")
+}
+
+func TestGetByIDV2(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+	mockLogRepo := new(mocks.LogmovieRepository)
+	mockUCase.On("GetByID", mock.Anything, shawshank.ID, domain.PlotShort).Return(shawshank, nil)
+	mockLogRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Movies")).Return(nil)
+
+	e := echo.New()
+	movieHttp.NewMovieHandler(e, mockUCase, mockLogRepo, movieRepo.Normalize, false)
+	req := httptest.NewRequest(echo.GET, "/v2/movies/"+shawshank.ID+"?plot=short", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"imdb_id": "tt0111161",
+		"title": "The Shawshank Redemption",
+		"year": 1994,
+		"released": "1994-10-14",
+		"runtime_minutes": 142,
+		"genres": ["Drama"],
+		"actors": ["Tim Robbins", "Morgan Freeman"],
+		"imdb_rating": 9.3
+	}`, rec.Body.String())
+	mockUCase.AssertExpectations(t)
+	mockLogRepo.AssertExpectations(t)
+}
+
+func TestFetchV2(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+	mockUCase.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Shawshank"}).Return(domain.MoviePage{
+		Movies:     []domain.Movies{{ID: "tt0111161", Title: "The Shawshank Redemption", Year: "1994", Type: "movie", Poster: "N/A"}},
+		Total:      1,
+		NextCursor: "2",
+	}, nil)
+
+	e := echo.New()
+	movieHttp.NewMovieHandler(e, mockUCase, nil, movieRepo.Normalize, false)
+	req := httptest.NewRequest(echo.GET, "/v2/movies?searchword=Shawshank", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-Total-Count"))
+	assert.JSONEq(t, `{
+		"movies": [{"imdb_id": "tt0111161", "title": "The Shawshank Redemption", "type": "movie", "year": 1994}],
+		"total": 1,
+		"next_cursor": "2"
+	}`, rec.Body.String())
+
+	req = httptest.NewRequest(echo.GET, "/v2/movies?searchword=a", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	mockUCase.AssertExpectations(t)
+}
 
+func TestNewMovieHandlerAuth(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+	mockUCase.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Shawshank"}).Return(domain.MoviePage{}, nil)
+
+	e := echo.New()
+	deny := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return echo.ErrUnauthorized
+		}
+	}
+	movieHttp.NewMovieHandler(e, mockUCase, nil, movieRepo.Normalize, false, deny)
+
+	for path, code := range map[string]int{
+		"/movies?searchword=Shawshank":    http.StatusOK,
+		"/v2/movies?searchword=Shawshank": http.StatusOK,
+		"/movies/" + shawshank.ID:         http.StatusUnauthorized,
+		"/v2/movies/" + shawshank.ID:      http.StatusUnauthorized,
+		"/unknown":                        http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(echo.GET, path, nil))
+		assert.Equal(t, code, rec.Code, path)
+	}
+	mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
 }
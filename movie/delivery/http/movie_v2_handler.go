@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// MovieListV2 represent a page of normalized movies served by the /v2 routes
+type MovieListV2 struct {
+	Movies     []domain.Movie `json:"movies"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
+}
+
+// FetchMovieV2 will fetch the movie based on given params, answering with the normalized movies
+func (a *MovieHandler) FetchMovieV2(c echo.Context) error {
+	page, err := a.fetch(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	res := MovieListV2{
+		Movies:     make([]domain.Movie, 0, len(page.Movies)),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	for _, m := range page.Movies {
+		res.Movies = append(res.Movies, a.Normalize(m))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetByIDV2 will get movie by given id, answering with the normalized movie
+func (a *MovieHandler) GetByIDV2(c echo.Context) error {
+	art, err := a.getByID(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, a.Normalize(art))
+}
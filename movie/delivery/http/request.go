@@ -0,0 +1,144 @@
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// imdbIDPattern matches the IMDb title ids, tt followed by at least seven digits
+var imdbIDPattern = regexp.MustCompile(`^tt\d{7,}$`)
+
+// minYear is the year of the first motion picture and yearsAnnounced how far ahead the announced titles go,
+// the years searched are bounded by both
+const (
+	minYear        = 1888
+	yearsAnnounced = 5
+)
+
+var validate = newValidator()
+
+// maxYear is the latest year a search accepts
+func maxYear() int {
+	return time.Now().Year() + yearsAnnounced
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// report the fields by the name the client sent them with
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"query", "param"} {
+			if name := f.Tag.Get(tag); name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+	_ = v.RegisterValidation("imdbid", func(fl validator.FieldLevel) bool {
+		return imdbIDPattern.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("year", func(fl validator.FieldLevel) bool {
+		year := fl.Field().String()
+		// Atoi accepts a sign, a year is digits only
+		if strings.TrimLeft(year, "0123456789") != "" {
+			return false
+		}
+		n, err := strconv.Atoi(year)
+		return err == nil && n >= minYear && n <= maxYear()
+	})
+	return v
+}
+
+// fetchMovieRequest represent the query params of GET /movies
+type fetchMovieRequest struct {
+	Searchword string `query:"searchword" validate:"required,min=2,max=100"`
+	Cursor     string `query:"cursor" validate:"max=64"`
+	Year       string `query:"year" validate:"omitempty,year"`
+	Type       string `query:"type" validate:"omitempty,oneof=movie series episode"`
+	Source     string `query:"source" validate:"omitempty,oneof=local remote auto"`
+}
+
+func newFetchMovieRequest(c echo.Context) fetchMovieRequest {
+	return fetchMovieRequest{
+		Searchword: strings.TrimSpace(c.QueryParam("searchword")),
+		Cursor:     c.QueryParam("cursor"),
+		Year:       c.QueryParam("year"),
+		Type:       strings.ToLower(c.QueryParam("type")),
+		Source:     strings.ToLower(c.QueryParam("source")),
+	}
+}
+
+// criteria will turn the validated request into the search criteria of the usecase
+func (r fetchMovieRequest) criteria() domain.SearchCriteria {
+	year, _ := strconv.Atoi(r.Year)
+	return domain.SearchCriteria{
+		Searchword: r.Searchword,
+		Year:       year,
+		Type:       domain.MovieType(r.Type),
+		Source:     domain.SearchSource(r.Source),
+		Cursor:     r.Cursor,
+	}
+}
+
+// getMovieRequest represent the params of GET /movies/:id
+type getMovieRequest struct {
+	ID   string `param:"id" validate:"required,imdbid"`
+	Plot string `query:"plot" validate:"omitempty,oneof=short full"`
+}
+
+func newGetMovieRequest(c echo.Context) getMovieRequest {
+	return getMovieRequest{
+		ID:   c.Param("id"),
+		Plot: strings.ToLower(c.QueryParam("plot")),
+	}
+}
+
+// validateRequest will check the request against its validate tags and return a *domain.ValidationError
+// listing every invalid field
+func validateRequest(req interface{}) error {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	fieldErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	res := &domain.ValidationError{}
+	for _, fe := range fieldErrs {
+		res.Fields = append(res.Fields, domain.FieldError{
+			Field:   fe.Field(),
+			Message: fieldMessage(fe),
+		})
+	}
+	return res
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "year":
+		return fmt.Sprintf("must be a year between %d and %d", minYear, maxYear())
+	case "oneof":
+		return "must be one of " + strings.Replace(fe.Param(), " ", ", ", -1)
+	case "imdbid":
+		return "must be an IMDb id such as tt0111161"
+	default:
+		return "is not valid"
+	}
+}
@@ -0,0 +1,225 @@
+package aggregate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+// Precedence maps a field of domain.Movies, named like the Go field e.g. Poster in any case, to the providers it is
+// taken from first. The providers left out of the list, and every provider of a field missing from the map, follow
+// in the order the providers were given
+type Precedence map[string][]string
+
+// ratingsField is the field the ratings of every provider are merged into
+const ratingsField = "Ratings"
+
+// field represent a merged string field of domain.Movies
+type field struct {
+	name string
+	get  func(m *domain.Movies) *string
+}
+
+var fields = []field{
+	{"Title", func(m *domain.Movies) *string { return &m.Title }},
+	{"Year", func(m *domain.Movies) *string { return &m.Year }},
+	{"Rated", func(m *domain.Movies) *string { return &m.Rated }},
+	{"Released", func(m *domain.Movies) *string { return &m.Released }},
+	{"Runtime", func(m *domain.Movies) *string { return &m.Runtime }},
+	{"Genre", func(m *domain.Movies) *string { return &m.Genre }},
+	{"Director", func(m *domain.Movies) *string { return &m.Director }},
+	{"Writer", func(m *domain.Movies) *string { return &m.Writer }},
+	{"Actors", func(m *domain.Movies) *string { return &m.Actors }},
+	{"Plot", func(m *domain.Movies) *string { return &m.Plot }},
+	{"Language", func(m *domain.Movies) *string { return &m.Language }},
+	{"Country", func(m *domain.Movies) *string { return &m.Country }},
+	{"Awards", func(m *domain.Movies) *string { return &m.Awards }},
+	{"Poster", func(m *domain.Movies) *string { return &m.Poster }},
+	{"Backdrop", func(m *domain.Movies) *string { return &m.Backdrop }},
+	{"Keywords", func(m *domain.Movies) *string { return &m.Keywords }},
+	{"Type", func(m *domain.Movies) *string { return &m.Type }},
+	{"Metascore", func(m *domain.Movies) *string { return &m.Metascore }},
+	{"ImdbRating", func(m *domain.Movies) *string { return &m.ImdbRating }},
+	{"ImdbVotes", func(m *domain.Movies) *string { return &m.ImdbVotes }},
+	{"DVD", func(m *domain.Movies) *string { return &m.DVD }},
+}
+
+type aggregateMovieRepository struct {
+	providers  []domain.MovieProvider
+	precedence Precedence
+}
+
+// NewAggregateMovieRepository will create a domain.MovieRepository merging the movie of every provider by imdbID.
+// Each field is taken from the first provider that has a value for it according to the precedence, the ratings of
+// all the providers are kept, and the merged movie records which provider supplied each field in its Sources.
+// The primary provider, the first one, failing with anything but domain.ErrNotFound leaves the merged movie
+// Partial. A secondary provider failing doesn't, the movie is then stored and cached like any other and its
+// missing fields are filled in once it expires, so an outage of a secondary provider doesn't send every lookup
+// back to the primary one. Searches go to the first provider that is a domain.MovieRepository too
+func NewAggregateMovieRepository(providers []domain.MovieProvider, precedence Precedence) domain.MovieRepository {
+	// the fields are matched case-insensitively, config keys being lowercased
+	lower := make(Precedence, len(precedence))
+	for f, names := range precedence {
+		lower[strings.ToLower(f)] = names
+	}
+
+	return &aggregateMovieRepository{
+		providers:  providers,
+		precedence: lower,
+	}
+}
+
+func (a *aggregateMovieRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (domain.MoviePage, error) {
+	for _, p := range a.providers {
+		if searcher, ok := p.(domain.MovieRepository); ok {
+			return searcher.Fetch(ctx, criteria)
+		}
+	}
+	return domain.MoviePage{}, domain.ErrInternalServerError
+}
+
+func (a *aggregateMovieRepository) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	ctx, span := tracing.Start(ctx, "aggregateMovieRepository.GetByID")
+	defer tracing.Finish(span, &err)
+
+	movies := make([]domain.Movies, len(a.providers))
+	errs := make([]error, len(a.providers))
+	var wg sync.WaitGroup
+	for i, p := range a.providers {
+		wg.Add(1)
+		go func(i int, p domain.MovieProvider) {
+			defer wg.Done()
+			movies[i], errs[i] = p.GetByID(ctx, id, plot)
+		}(i, p)
+	}
+	wg.Wait()
+
+	found := make(map[string]domain.Movies, len(a.providers))
+	partial := false
+	err = domain.ErrNotFound
+	for i, p := range a.providers {
+		switch {
+		case errs[i] == nil && movies[i].ID != "" && movies[i].ID != id:
+			logger.FromContext(ctx).Warnf("provider %s answered %s with %s", p.Name(), id, movies[i].ID)
+		case errs[i] == nil:
+			found[p.Name()] = movies[i]
+		case !errors.Is(errs[i], domain.ErrNotFound):
+			logger.FromContext(ctx).Warnf("provider %s get %s: %s", p.Name(), id, errs[i])
+			partial = partial || i == 0
+			// the first provider failing is reported when no provider knows the movie
+			if err == domain.ErrNotFound {
+				err = errs[i]
+			}
+		}
+	}
+	if len(found) == 0 {
+		return domain.Movies{}, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("aggregate.providers", len(found)),
+		attribute.Bool("aggregate.partial", partial),
+	)
+	res = a.merge(id, found)
+	res.Partial = partial
+	return res, nil
+}
+
+// merge will build the movie out of the ones found by the providers, keyed by provider name
+func (a *aggregateMovieRepository) merge(id string, found map[string]domain.Movies) domain.Movies {
+	res := domain.Movies{ID: id, Sources: make(map[string]string)}
+
+	for _, f := range fields {
+		for _, name := range a.order(f.name) {
+			m, ok := found[name]
+			if !ok {
+				continue
+			}
+			if v := *f.get(&m); present(v) {
+				*f.get(&res) = v
+				res.Sources[f.name] = name
+				break
+			}
+		}
+	}
+
+	// a rating source rated by several providers is kept once, as the first provider in order has it
+	var ratedBy []string
+	for _, name := range a.order(ratingsField) {
+		m, ok := found[name]
+		if !ok {
+			continue
+		}
+		added := false
+		for _, r := range m.Ratings {
+			if present(r.Value) && !hasRating(res.Ratings, r.Source) {
+				res.Ratings = append(res.Ratings, r)
+				added = true
+			}
+		}
+		if added {
+			ratedBy = append(ratedBy, name)
+		}
+	}
+	if len(ratedBy) > 0 {
+		res.Sources[ratingsField] = strings.Join(ratedBy, ", ")
+	}
+
+	return res
+}
+
+// order returns the providers the field is taken from, the ones of its precedence first
+func (a *aggregateMovieRepository) order(field string) []string {
+	names := make([]string, 0, len(a.providers))
+	for _, name := range a.precedence[strings.ToLower(field)] {
+		if a.known(name) && !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for _, p := range a.providers {
+		if !contains(names, p.Name()) {
+			names = append(names, p.Name())
+		}
+	}
+	return names
+}
+
+func (a *aggregateMovieRepository) known(name string) bool {
+	for _, p := range a.providers {
+		if p.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// present reports whether the provider has a value, OMDb writing N/A for the values it doesn't have
+func present(v string) bool {
+	v = strings.TrimSpace(v)
+	return v != "" && !strings.EqualFold(v, "N/A")
+}
+
+func hasRating(ratings []domain.Rating, source string) bool {
+	for _, r := range ratings {
+		if r.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package aggregate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/repository/aggregate"
+	movieRepo "github.com/bxcodec/go-clean-arch/movie/repository/movie"
+)
+
+var (
+	omdbMovie = domain.Movies{
+		ID:         "tt0111161",
+		Title:      "The Shawshank Redemption",
+		Year:       "1994",
+		Plot:       "Two imprisoned men bond over a number of years.",
+		Poster:     "https://m.media-amazon.com/poster.jpg",
+		Awards:     "N/A",
+		ImdbRating: "9.3",
+		Ratings: []domain.Rating{
+			{Source: "Internet Movie Database", Value: "9.3/10"},
+			{Source: "Rotten Tomatoes", Value: "91%"},
+		},
+	}
+	tmdbMovie = domain.Movies{
+		ID:       "tt0111161",
+		Title:    "The Shawshank Redemption",
+		Year:     "1994",
+		Plot:     "Framed in the 1940s for the double murder of his wife and her lover...",
+		Poster:   "https://image.tmdb.org/t/p/original/poster.jpg",
+		Backdrop: "https://image.tmdb.org/t/p/original/backdrop.jpg",
+		Keywords: "prison, corruption",
+		Awards:   "",
+		Ratings: []domain.Rating{
+			{Source: "TMDb", Value: "8.7/10"},
+			{Source: "Rotten Tomatoes", Value: "90%"},
+		},
+	}
+)
+
+func newProvider(name string, m domain.Movies, err error) *mocks.MovieProvider {
+	p := new(mocks.MovieProvider)
+	p.On("Name").Return(name)
+	p.On("GetByID", mock.Anything, "tt0111161", domain.PlotFull).Return(m, err)
+	return p
+}
+
+func TestGetByID(t *testing.T) {
+	omdb := newProvider("omdb", omdbMovie, nil)
+	tmdb := newProvider("tmdb", tmdbMovie, nil)
+	r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, aggregate.Precedence{
+		"poster": {"tmdb"},
+	})
+
+	res, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	require.NoError(t, err)
+	assert.Equal(t, "tt0111161", res.ID)
+	assert.Equal(t, omdbMovie.Plot, res.Plot)
+	assert.Equal(t, tmdbMovie.Poster, res.Poster)
+	assert.Equal(t, tmdbMovie.Backdrop, res.Backdrop)
+	assert.Equal(t, tmdbMovie.Keywords, res.Keywords)
+	assert.Empty(t, res.Awards)
+	assert.Equal(t, []domain.Rating{
+		{Source: "Internet Movie Database", Value: "9.3/10"},
+		{Source: "Rotten Tomatoes", Value: "91%"},
+		{Source: "TMDb", Value: "8.7/10"},
+	}, res.Ratings)
+	assert.Equal(t, map[string]string{
+		"Title":      "omdb",
+		"Year":       "omdb",
+		"Plot":       "omdb",
+		"Poster":     "tmdb",
+		"Backdrop":   "tmdb",
+		"Keywords":   "tmdb",
+		"ImdbRating": "omdb",
+		"Ratings":    "omdb, tmdb",
+	}, res.Sources)
+	omdb.AssertExpectations(t)
+	tmdb.AssertExpectations(t)
+}
+
+func TestGetByIDPartial(t *testing.T) {
+	t.Run("provider-not-found", func(t *testing.T) {
+		omdb := newProvider("omdb", omdbMovie, nil)
+		tmdb := newProvider("tmdb", domain.Movies{}, domain.ErrNotFound)
+		r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, nil)
+
+		res, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		require.NoError(t, err)
+		assert.Equal(t, omdbMovie.Poster, res.Poster)
+		assert.Equal(t, "omdb", res.Sources["Poster"])
+		assert.False(t, res.Partial)
+	})
+
+	t.Run("provider-down", func(t *testing.T) {
+		omdb := newProvider("omdb", domain.Movies{}, domain.ErrServiceUnavailable)
+		tmdb := newProvider("tmdb", tmdbMovie, nil)
+		r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, nil)
+
+		res, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		require.NoError(t, err)
+		assert.Equal(t, tmdbMovie.Plot, res.Plot)
+		assert.Equal(t, "tmdb", res.Sources["Plot"])
+		assert.True(t, res.Partial)
+	})
+
+	t.Run("secondary-provider-down", func(t *testing.T) {
+		omdb := newProvider("omdb", omdbMovie, nil)
+		tmdb := newProvider("tmdb", domain.Movies{}, domain.ErrServiceUnavailable)
+		r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, nil)
+
+		res, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		require.NoError(t, err)
+		assert.Equal(t, omdbMovie.Plot, res.Plot)
+		assert.Empty(t, res.Backdrop)
+		assert.False(t, res.Partial)
+	})
+
+	t.Run("other-movie", func(t *testing.T) {
+		omdb := newProvider("omdb", omdbMovie, nil)
+		tmdb := newProvider("tmdb", domain.Movies{ID: "tt0068646", Backdrop: "/godfather.jpg"}, nil)
+		r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, nil)
+
+		res, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		require.NoError(t, err)
+		assert.Empty(t, res.Backdrop)
+	})
+}
+
+func TestGetByIDError(t *testing.T) {
+	t.Run("not-found", func(t *testing.T) {
+		omdb := newProvider("omdb", domain.Movies{}, domain.ErrNotFound)
+		tmdb := newProvider("tmdb", domain.Movies{}, domain.ErrNotFound)
+		r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, nil)
+
+		_, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		omdb := newProvider("omdb", domain.Movies{}, domain.ErrNotFound)
+		tmdb := newProvider("tmdb", domain.Movies{}, errors.New("tmdb: unexpected status 503"))
+		r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, nil)
+
+		_, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.EqualError(t, err, "tmdb: unexpected status 503")
+	})
+}
+
+func TestFetch(t *testing.T) {
+	criteria := domain.SearchCriteria{Searchword: "Shawshank"}
+	page := domain.MoviePage{Movies: []domain.Movies{{ID: "tt0111161"}}, Total: 1}
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("Fetch", mock.Anything, criteria).Return(page, nil).Once()
+
+	tmdb := new(mocks.MovieProvider)
+	r := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{tmdb, movieRepo.NewOMDbProvider(mockMovieRepo)}, nil)
+
+	res, err := r.Fetch(context.TODO(), criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, page, res)
+	mockMovieRepo.AssertExpectations(t)
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
+)
+
+type cachedMovieRepository struct {
+	repo       domain.MovieRepository
+	cache      domain.Cache
+	getByIDTTL time.Duration
+	fetchTTL   time.Duration
+	timeout    time.Duration
+	group      singleflight.Group
+}
+
+// NewCachedMovieRepository will create a read-through cache in front of the given domain.MovieRepository.
+// Entries are stored in the given domain.Cache with a TTL per operation,
+// and concurrent lookups for the same key only hit the underlying repository once, bounded by timeout.
+func NewCachedMovieRepository(repo domain.MovieRepository, c domain.Cache, getByIDTTL, fetchTTL, timeout time.Duration) domain.MovieRepository {
+	return &cachedMovieRepository{
+		repo:       repo,
+		cache:      c,
+		getByIDTTL: getByIDTTL,
+		fetchTTL:   fetchTTL,
+		timeout:    timeout,
+	}
+}
+
+func (c *cachedMovieRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	// the searchword goes last so it can't be mistaken for another field
+	key := fmt.Sprintf("fetch:%d:%s:%s:%s:%s", criteria.Year, criteria.Type, criteria.Source, criteria.Cursor,
+		criteria.Searchword)
+	if c.load(ctx, key, &res) {
+		return res, nil
+	}
+
+	v, err := c.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		page, err := c.repo.Fetch(ctx, criteria)
+		if err != nil {
+			return nil, err
+		}
+
+		// a catalog page standing in for the remote search is served but not cached, the next search may reach it
+		if !page.Fallback {
+			c.save(ctx, key, page, c.fetchTTL)
+		}
+		return page, nil
+	})
+	if err != nil {
+		return
+	}
+
+	return v.(domain.MoviePage), nil
+}
+
+func (c *cachedMovieRepository) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	key := fmt.Sprintf("movie:%s:%s", id, plot)
+	if c.load(ctx, key, &res) {
+		return res, nil
+	}
+
+	v, err := c.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		movie, err := c.repo.GetByID(ctx, id, plot)
+		if err != nil {
+			return nil, err
+		}
+
+		// a partial movie is served but not cached, the next lookup may find the missing fields
+		if !movie.Partial {
+			c.save(ctx, key, movie, c.getByIDTTL)
+		}
+		return movie, nil
+	})
+	if err != nil {
+		return
+	}
+
+	return v.(domain.Movies), nil
+}
+
+// do will run fn once for all the concurrent lookups of key. The shared call runs on a context detached from the
+// caller that started it and bounded by the repository timeout, so a caller giving up doesn't fail the others.
+// Each caller still stops waiting when its own ctx is done
+func (c *cachedMovieRepository) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx := context.Context(detachedContext{ctx})
+		if c.timeout > 0 {
+			var cancel context.CancelFunc
+			loadCtx, cancel = context.WithTimeout(loadCtx, c.timeout)
+			defer cancel()
+		}
+
+		return fn(loadCtx)
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// detachedContext keeps the values of the parent, the logger and the trace, but not its deadline nor cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (d detachedContext) Value(key interface{}) interface{} { return d.parent.Value(key) }
+
+// load will decode the cached value of key into dest. A failing cache is treated as a miss
+func (c *cachedMovieRepository) load(ctx context.Context, key string, dest interface{}) bool {
+	byt, err := c.cache.Get(ctx, key)
+	if err != nil {
+		if err != domain.ErrCacheMiss {
+			logger.FromContext(ctx).Warnf("cache get %s: %s", key, err)
+		}
+		return false
+	}
+
+	if err = json.Unmarshal(byt, dest); err != nil {
+		logger.FromContext(ctx).Warnf("cache decode %s: %s", key, err)
+		return false
+	}
+
+	return true
+}
+
+func (c *cachedMovieRepository) save(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	byt, err := json.Marshal(value)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("cache encode %s: %s", key, err)
+		return
+	}
+
+	if err = c.cache.Set(ctx, key, byt, ttl); err != nil {
+		logger.FromContext(ctx).Warnf("cache set %s: %s", key, err)
+	}
+}
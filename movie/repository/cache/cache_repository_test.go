@@ -0,0 +1,261 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/cache/memory"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/repository/aggregate"
+	"github.com/bxcodec/go-clean-arch/movie/repository/cache"
+)
+
+func TestGetByID(t *testing.T) {
+	mockMovie := domain.Movies{ID: "tt0111161", Title: "The Shawshank Redemption"}
+
+	t.Run("success-cached", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+		for i := 0; i < 3; i++ {
+			res, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+			assert.NoError(t, err)
+			assert.Equal(t, mockMovie, res)
+		}
+
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-not-cached", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, errors.New("Unexpected")).Twice()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+			assert.Error(t, err)
+		}
+
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("partial-not-cached", func(t *testing.T) {
+		partial := mockMovie
+		partial.Partial = true
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(partial, nil).Once()
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+		res, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.True(t, res.Partial)
+		res, err = c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.Equal(t, mockMovie, res)
+
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Twice()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Millisecond, time.Minute, time.Second)
+
+		_, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+		_, err = c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("evicted", func(t *testing.T) {
+		otherMovie := domain.Movies{ID: "tt0068646", Title: "The Godfather"}
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Twice()
+		mockMovieRepo.On("GetByID", mock.Anything, otherMovie.ID, domain.PlotFull).Return(otherMovie, nil).Once()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(1), time.Minute, time.Minute, time.Second)
+
+		for _, id := range []string{mockMovie.ID, otherMovie.ID, mockMovie.ID} {
+			_, err := c.GetByID(context.TODO(), id, domain.PlotFull)
+			assert.NoError(t, err)
+		}
+
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("cache-unavailable", func(t *testing.T) {
+		mockCache := new(mocks.Cache)
+		mockCache.On("Get", mock.Anything, "movie:"+mockMovie.ID+":full").Return(nil, errors.New("connection refused")).Once()
+		mockCache.On("Set", mock.Anything, "movie:"+mockMovie.ID+":full", mock.Anything, time.Minute).Return(errors.New("connection refused")).Once()
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, mockCache, time.Minute, time.Minute, time.Second)
+
+		res, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.Equal(t, mockMovie, res)
+
+		mockCache.AssertExpectations(t)
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("singleflight", func(t *testing.T) {
+		release := make(chan struct{})
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).
+			Run(func(args mock.Arguments) { <-release }).
+			Return(mockMovie, nil).Once()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+				assert.NoError(t, err)
+				assert.Equal(t, mockMovie, res)
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("singleflight-caller-cancelled", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		var loadErr error
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).
+			Run(func(args mock.Arguments) {
+				close(started)
+				<-release
+				loadErr = args.Get(0).(context.Context).Err()
+			}).
+			Return(mockMovie, nil).Once()
+		c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+		// the caller starting the load gives up, it stops waiting but the load goes on for the others
+		first, cancel := context.WithCancel(context.TODO())
+		firstErr := make(chan error)
+		go func() {
+			_, err := c.GetByID(first, mockMovie.ID, domain.PlotFull)
+			firstErr <- err
+		}()
+		<-started
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+			assert.NoError(t, err)
+			assert.Equal(t, mockMovie, res)
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		cancel()
+		assert.Equal(t, context.Canceled, <-firstErr)
+		close(release)
+		wg.Wait()
+
+		assert.NoError(t, loadErr)
+		mockMovieRepo.AssertExpectations(t)
+	})
+}
+
+func TestFetch(t *testing.T) {
+	mockListMovie := []domain.Movies{
+		{ID: "tt0372784", Title: "Batman Begins"},
+		{ID: "tt0468569", Title: "The Dark Knight"},
+	}
+	firstPage := domain.MoviePage{Movies: mockListMovie, Total: 42, NextCursor: "2"}
+	secondPage := domain.MoviePage{Movies: mockListMovie, Total: 42, NextCursor: "3", PrevCursor: "1"}
+
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman", Cursor: "1"}).Return(firstPage, nil).Once()
+	mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman", Cursor: "2"}).Return(secondPage, nil).Once()
+	c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+	for i := 0; i < 2; i++ {
+		page, err := c.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Cursor: "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, firstPage, page)
+	}
+
+	page, err := c.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Cursor: "2"})
+	assert.NoError(t, err)
+	assert.Equal(t, secondPage, page)
+
+	// the filters are part of the key
+	filtered := domain.SearchCriteria{Searchword: "Batman", Year: 2005, Type: domain.MovieTypeMovie, Cursor: "1"}
+	mockMovieRepo.On("Fetch", mock.Anything, filtered).Return(secondPage, nil).Once()
+	page, err = c.Fetch(context.TODO(), filtered)
+	assert.NoError(t, err)
+	assert.Equal(t, secondPage, page)
+
+	local := domain.SearchCriteria{Searchword: "Batman", Source: domain.SearchSourceLocal, Cursor: "1"}
+	mockMovieRepo.On("Fetch", mock.Anything, local).Return(secondPage, nil).Once()
+	page, err = c.Fetch(context.TODO(), local)
+	assert.NoError(t, err)
+	assert.Equal(t, secondPage, page)
+
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestFetchFallbackNotCached(t *testing.T) {
+	criteria := domain.SearchCriteria{Searchword: "Batman", Source: domain.SearchSourceAuto}
+	fallbackPage := domain.MoviePage{Movies: []domain.Movies{{ID: "tt0372784"}}, Total: 1, Fallback: true}
+	remotePage := domain.MoviePage{Movies: []domain.Movies{{ID: "tt0372784"}, {ID: "tt0468569"}}, Total: 2}
+
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("Fetch", mock.Anything, criteria).Return(fallbackPage, nil).Once()
+	mockMovieRepo.On("Fetch", mock.Anything, criteria).Return(remotePage, nil).Once()
+	c := cache.NewCachedMovieRepository(mockMovieRepo, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+	page, err := c.Fetch(context.TODO(), criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, fallbackPage, page)
+	for i := 0; i < 2; i++ {
+		page, err = c.Fetch(context.TODO(), criteria)
+		assert.NoError(t, err)
+		assert.Equal(t, remotePage, page)
+	}
+
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestGetByIDSecondaryProviderDown(t *testing.T) {
+	mockMovie := domain.Movies{ID: "tt0111161", Title: "The Shawshank Redemption"}
+	omdb := new(mocks.MovieProvider)
+	omdb.On("Name").Return("omdb")
+	omdb.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+	tmdb := new(mocks.MovieProvider)
+	tmdb.On("Name").Return("tmdb")
+	tmdb.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, domain.ErrServiceUnavailable)
+	merged := aggregate.NewAggregateMovieRepository([]domain.MovieProvider{omdb, tmdb}, nil)
+	c := cache.NewCachedMovieRepository(merged, memory.NewMemoryCache(10), time.Minute, time.Minute, time.Second)
+
+	for i := 0; i < 2; i++ {
+		res, err := c.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.Equal(t, mockMovie.Title, res.Title)
+	}
+
+	// the second lookup is answered from the cache, OMDb being called once
+	omdb.AssertExpectations(t)
+	tmdb.AssertNumberOfCalls(t, "GetByID", 1)
+}
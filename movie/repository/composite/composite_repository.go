@@ -0,0 +1,113 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+type compositeMovieRepository struct {
+	local         domain.MovieCatalogRepository
+	remote        domain.MovieRepository
+	searchTimeout time.Duration
+}
+
+// NewCompositeMovieRepository will create a domain.MovieRepository looking movies up in the local catalog first.
+// A movie missing from the catalog is fetched from the remote repository and written back, unless Partial, so the
+// movies looked up once no longer depend on the remote one. Searches go where their source says, the automatic ones go to the
+// remote repository, which knows every title, and fall back to the catalog while it is unavailable or hasn't
+// answered within searchTimeout. A zero searchTimeout leaves the remote search the whole request deadline
+func NewCompositeMovieRepository(local domain.MovieCatalogRepository, remote domain.MovieRepository, searchTimeout time.Duration) domain.MovieRepository {
+	return &compositeMovieRepository{
+		local:         local,
+		remote:        remote,
+		searchTimeout: searchTimeout,
+	}
+}
+
+func (c *compositeMovieRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	ctx, span := tracing.Start(ctx, "compositeMovieRepository.Fetch")
+	defer tracing.Finish(span, &err)
+	span.SetAttributes(attribute.String("search.source", string(criteria.Source)))
+
+	switch criteria.Source {
+	case domain.SearchSourceLocal:
+		return c.local.Fetch(ctx, criteria)
+	case domain.SearchSourceRemote:
+		return c.remote.Fetch(ctx, criteria)
+	}
+
+	remoteCtx := ctx
+	if c.searchTimeout > 0 {
+		var cancel context.CancelFunc
+		remoteCtx, cancel = context.WithTimeout(ctx, c.searchTimeout)
+		defer cancel()
+	}
+
+	res, err = c.remote.Fetch(remoteCtx, criteria)
+	if err == nil || !unavailable(err) {
+		return
+	}
+
+	page, errLocal := c.local.Fetch(ctx, criteria)
+	if errLocal != nil {
+		if !errors.Is(errLocal, domain.ErrNotFound) {
+			logger.FromContext(ctx).Warnf("catalog search %q: %s", criteria.Searchword, errLocal)
+		}
+		return
+	}
+
+	span.SetAttributes(attribute.Bool("catalog.fallback", true))
+	page.Fallback = true
+	return page, nil
+}
+
+func (c *compositeMovieRepository) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	ctx, span := tracing.Start(ctx, "compositeMovieRepository.GetByID")
+	defer tracing.Finish(span, &err)
+
+	res, err = c.local.GetByID(ctx, id, plot)
+	if err == nil {
+		span.SetAttributes(attribute.Bool("catalog.hit", true))
+		return
+	}
+	// a failing catalog must not fail the lookup, the remote repository still answers it
+	if !errors.Is(err, domain.ErrNotFound) {
+		logger.FromContext(ctx).Warnf("catalog get %s: %s", id, err)
+	}
+	span.SetAttributes(attribute.Bool("catalog.hit", false))
+
+	res, err = c.remote.GetByID(ctx, id, plot)
+	if err != nil {
+		return
+	}
+	// a movie the primary provider failed to answer is served but not stored, it would never be refreshed
+	if res.Partial {
+		return res, nil
+	}
+
+	if errStore := c.local.Store(ctx, &res, plot); errStore != nil {
+		logger.FromContext(ctx).Warnf("catalog store %s: %s", id, errStore)
+	}
+	return res, nil
+}
+
+// unavailable reports whether err means the remote repository couldn't answer, rather than the search having no
+// valid answer
+func unavailable(err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrBadParamInput),
+		errors.Is(err, domain.ErrTooManyResults),
+		errors.Is(err, context.Canceled):
+		return false
+	default:
+		return true
+	}
+}
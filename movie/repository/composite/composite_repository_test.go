@@ -0,0 +1,191 @@
+package composite_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/repository/composite"
+)
+
+var mockMovie = domain.Movies{ID: "tt0111161", Title: "The Shawshank Redemption", Plot: "Two imprisoned men bond."}
+
+func TestGetByID(t *testing.T) {
+	t.Run("local-hit", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		local.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		res, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.Equal(t, mockMovie, res)
+		local.AssertExpectations(t)
+		remote.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("miss-write-back", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		local.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotShort).Return(domain.Movies{}, domain.ErrNotFound).Once()
+		remote.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotShort).Return(mockMovie, nil).Once()
+		local.On("Store", mock.Anything, &mockMovie, domain.PlotShort).Return(nil).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		res, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotShort)
+		assert.NoError(t, err)
+		assert.Equal(t, mockMovie, res)
+		local.AssertExpectations(t)
+		remote.AssertExpectations(t)
+	})
+
+	t.Run("catalog-down", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		local.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, errors.New("connection refused")).Once()
+		remote.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+		local.On("Store", mock.Anything, mock.Anything, domain.PlotFull).Return(errors.New("connection refused")).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		res, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.Equal(t, mockMovie, res)
+		local.AssertExpectations(t)
+	})
+
+	t.Run("partial-not-stored", func(t *testing.T) {
+		partial := mockMovie
+		partial.Partial = true
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		local.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, domain.ErrNotFound).Once()
+		remote.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(partial, nil).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		res, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.Equal(t, partial, res)
+		local.AssertNotCalled(t, "Store", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("remote-not-found", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		local.On("GetByID", mock.Anything, "tt0000000", domain.PlotFull).Return(domain.Movies{}, domain.ErrNotFound).Once()
+		remote.On("GetByID", mock.Anything, "tt0000000", domain.PlotFull).Return(domain.Movies{}, domain.ErrNotFound).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		_, err := r.GetByID(context.TODO(), "tt0000000", domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+		local.AssertNotCalled(t, "Store", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetch(t *testing.T) {
+	criteria := domain.SearchCriteria{Searchword: "Shawshank"}
+	remotePage := domain.MoviePage{Movies: []domain.Movies{mockMovie}, Total: 1}
+	localPage := domain.MoviePage{Movies: []domain.Movies{{ID: mockMovie.ID}}, Total: 1}
+
+	t.Run("remote", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		remote.On("Fetch", mock.Anything, criteria).Return(remotePage, nil).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		res, err := r.Fetch(context.TODO(), criteria)
+		assert.NoError(t, err)
+		assert.Equal(t, remotePage, res)
+		local.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("remote-unavailable", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		remote.On("Fetch", mock.Anything, criteria).Return(domain.MoviePage{}, domain.ErrServiceUnavailable).Once()
+		local.On("Fetch", mock.Anything, criteria).Return(localPage, nil).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		res, err := r.Fetch(context.TODO(), criteria)
+		assert.NoError(t, err)
+		assert.True(t, res.Fallback)
+		assert.Equal(t, localPage.Movies, res.Movies)
+	})
+
+	t.Run("remote-unavailable-catalog-empty", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		remote.On("Fetch", mock.Anything, criteria).Return(domain.MoviePage{}, domain.ErrServiceUnavailable).Once()
+		local.On("Fetch", mock.Anything, criteria).Return(domain.MoviePage{}, domain.ErrNotFound).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		_, err := r.Fetch(context.TODO(), criteria)
+		assert.Equal(t, domain.ErrServiceUnavailable, err)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		remote.On("Fetch", mock.Anything, criteria).Return(domain.MoviePage{}, domain.ErrNotFound).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		_, err := r.Fetch(context.TODO(), criteria)
+		assert.Equal(t, domain.ErrNotFound, err)
+		local.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchSource(t *testing.T) {
+	localPage := domain.MoviePage{Movies: []domain.Movies{{ID: mockMovie.ID}}, Total: 1}
+
+	t.Run("local", func(t *testing.T) {
+		criteria := domain.SearchCriteria{Searchword: "prison", Source: domain.SearchSourceLocal}
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		local.On("Fetch", mock.Anything, criteria).Return(localPage, nil).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		res, err := r.Fetch(context.TODO(), criteria)
+		assert.NoError(t, err)
+		assert.Equal(t, localPage, res)
+		remote.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		criteria := domain.SearchCriteria{Searchword: "prison", Source: domain.SearchSourceRemote}
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		remote.On("Fetch", mock.Anything, criteria).Return(domain.MoviePage{}, domain.ErrRateLimited).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 0)
+
+		_, err := r.Fetch(context.TODO(), criteria)
+		assert.Equal(t, domain.ErrRateLimited, err)
+		local.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("auto-slow-remote", func(t *testing.T) {
+		criteria := domain.SearchCriteria{Searchword: "prison", Source: domain.SearchSourceAuto}
+		local := new(mocks.MovieCatalogRepository)
+		remote := new(mocks.MovieRepository)
+		remote.On("Fetch", mock.Anything, criteria).Return(func(ctx context.Context, _ domain.SearchCriteria) domain.MoviePage {
+			<-ctx.Done()
+			return domain.MoviePage{}
+		}, func(ctx context.Context, _ domain.SearchCriteria) error {
+			return ctx.Err()
+		}).Once()
+		local.On("Fetch", mock.Anything, criteria).Return(localPage, nil).Once()
+		r := composite.NewCompositeMovieRepository(local, remote, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+		defer cancel()
+		res, err := r.Fetch(ctx, criteria)
+		assert.NoError(t, err)
+		assert.True(t, res.Fallback)
+		assert.Equal(t, localPage.Movies, res.Movies)
+	})
+}
@@ -2,29 +2,45 @@ package repository
 
 import (
 	"encoding/base64"
-	"time"
+	"strconv"
+	"strings"
 )
 
 const (
-	timeFormat = "2006-01-02T15:04:05.999Z07:00" // reduce precision from RFC3339Nano as date format
+	cursorPrefix = "page:"
 )
 
-// DecodeCursor will decode cursor from user for mysql
-func DecodeCursor(encodedTime string) (time.Time, error) {
-	byt, err := base64.StdEncoding.DecodeString(encodedTime)
+// DecodeCursor will decode the page number from the opaque cursor given by user.
+// An empty cursor points to the first page
+func DecodeCursor(encodedCursor string) (int, error) {
+	if encodedCursor == "" {
+		return 1, nil
+	}
+
+	byt, err := base64.URLEncoding.DecodeString(encodedCursor)
 	if err != nil {
-		return time.Time{}, err
+		return 0, err
+	}
+
+	pageString := string(byt)
+	if !strings.HasPrefix(pageString, cursorPrefix) {
+		return 0, strconv.ErrSyntax
 	}
 
-	timeString := string(byt)
-	t, err := time.Parse(timeFormat, timeString)
+	page, err := strconv.Atoi(strings.TrimPrefix(pageString, cursorPrefix))
+	if err != nil {
+		return 0, err
+	}
+	if page < 1 {
+		return 0, strconv.ErrRange
+	}
 
-	return t, err
+	return page, nil
 }
 
-// EncodeCursor will encode cursor from mysql to user
-func EncodeCursor(t time.Time) string {
-	timeString := t.Format(timeFormat)
+// EncodeCursor will encode the page number into an opaque cursor for user
+func EncodeCursor(page int) string {
+	pageString := cursorPrefix + strconv.Itoa(page)
 
-	return base64.StdEncoding.EncodeToString([]byte(timeString))
+	return base64.URLEncoding.EncodeToString([]byte(pageString))
 }
@@ -0,0 +1,24 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/movie/repository"
+)
+
+func TestCursor(t *testing.T) {
+	page, err := repository.DecodeCursor(repository.EncodeCursor(7))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, page)
+
+	page, err = repository.DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, page)
+
+	for _, invalid := range []string{"7", "not-base64!", repository.EncodeCursor(0)} {
+		_, err = repository.DecodeCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,55 @@
+package instrument
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/metrics"
+)
+
+type instrumentedMovieRepository struct {
+	repo     domain.MovieRepository
+	provider string
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewInstrumentedMovieRepository will count and time every call made to the given domain.MovieRepository,
+// labelled with the provider name and the outcome of the call
+func NewInstrumentedMovieRepository(repo domain.MovieRepository, reg prometheus.Registerer, provider string) (domain.MovieRepository, error) {
+	calls, err := metrics.NewCounterVec(reg, "movie_repository_calls_total",
+		"Calls made to the movie provider by method and outcome.", "provider", "method", "outcome")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := metrics.NewHistogramVec(reg, "movie_repository_duration_seconds",
+		"Latency of the calls made to the movie provider.", "provider", "method")
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedMovieRepository{
+		repo:     repo,
+		provider: provider,
+		calls:    calls,
+		duration: duration,
+	}, nil
+}
+
+func (i *instrumentedMovieRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	defer i.observe("Fetch", time.Now(), &err)
+	return i.repo.Fetch(ctx, criteria)
+}
+
+func (i *instrumentedMovieRepository) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	defer i.observe("GetByID", time.Now(), &err)
+	return i.repo.GetByID(ctx, id, plot)
+}
+
+func (i *instrumentedMovieRepository) observe(method string, start time.Time, err *error) {
+	i.duration.WithLabelValues(i.provider, method).Observe(time.Since(start).Seconds())
+	i.calls.WithLabelValues(i.provider, method, metrics.Outcome(*err)).Inc()
+}
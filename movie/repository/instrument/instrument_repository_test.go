@@ -0,0 +1,71 @@
+package instrument_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/repository/instrument"
+)
+
+func TestInstrumentedMovieRepository(t *testing.T) {
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("GetByID", mock.Anything, "tt0111161", domain.PlotFull).Return(domain.Movies{ID: "tt0111161"}, nil).Once()
+	mockMovieRepo.On("GetByID", mock.Anything, "tt0000000", domain.PlotFull).Return(domain.Movies{}, domain.ErrNotFound).Once()
+	mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(domain.MoviePage{}, &domain.RateLimitError{}).Once()
+
+	reg := prometheus.NewRegistry()
+	repo, err := instrument.NewInstrumentedMovieRepository(mockMovieRepo, reg, "omdb")
+	require.NoError(t, err)
+
+	res, err := repo.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	assert.NoError(t, err)
+	assert.Equal(t, "tt0111161", res.ID)
+	_, err = repo.GetByID(context.TODO(), "tt0000000", domain.PlotFull)
+	assert.Equal(t, domain.ErrNotFound, err)
+	_, err = repo.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+	assert.Error(t, err)
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	buf := rec.Body
+	assert.Contains(t, buf.String(), `movie_repository_calls_total{method="GetByID",outcome="ok",provider="omdb"} 1`)
+	assert.Contains(t, buf.String(), `movie_repository_calls_total{method="GetByID",outcome="not_found",provider="omdb"} 1`)
+	assert.Contains(t, buf.String(), `movie_repository_calls_total{method="Fetch",outcome="rate_limited",provider="omdb"} 1`)
+	assert.Contains(t, buf.String(), `movie_repository_duration_seconds_count{method="GetByID",provider="omdb"} 2`)
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestInstrumentedMovieRepositoryProviders(t *testing.T) {
+	omdbRepo := new(mocks.MovieRepository)
+	omdbRepo.On("GetByID", mock.Anything, "tt0111161", domain.PlotFull).Return(domain.Movies{ID: "tt0111161"}, nil).Once()
+	tmdbRepo := new(mocks.MovieRepository)
+	tmdbRepo.On("GetByID", mock.Anything, "tt0111161", domain.PlotFull).Return(domain.Movies{}, domain.ErrNotFound).Once()
+
+	// the providers share the series of a single registry
+	reg := prometheus.NewRegistry()
+	omdb, err := instrument.NewInstrumentedMovieRepository(omdbRepo, reg, "omdb")
+	require.NoError(t, err)
+	tmdb, err := instrument.NewInstrumentedMovieRepository(tmdbRepo, reg, "tmdb")
+	require.NoError(t, err)
+
+	_, err = omdb.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	assert.NoError(t, err)
+	_, err = tmdb.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	assert.Equal(t, domain.ErrNotFound, err)
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	buf := rec.Body
+	assert.Contains(t, buf.String(), `movie_repository_calls_total{method="GetByID",outcome="ok",provider="omdb"} 1`)
+	assert.Contains(t, buf.String(), `movie_repository_calls_total{method="GetByID",outcome="not_found",provider="tmdb"} 1`)
+}
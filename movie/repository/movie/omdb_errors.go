@@ -0,0 +1,36 @@
+package movie
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// omdbStatus represent the status fields OMDb sends along every payload,
+// it answers HTTP 200 with Response "False" when the lookup failed
+type omdbStatus struct {
+	Response string `json:"Response"`
+	Error    string `json:"Error"`
+}
+
+// err will translate the OMDb status into a domain error, nil when the lookup succeeded
+func (s omdbStatus) err() error {
+	if s.Response != "False" {
+		return nil
+	}
+
+	msg := strings.ToLower(s.Error)
+	switch {
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "incorrect imdb id"):
+		return domain.ErrNotFound
+	case strings.Contains(msg, "api key"):
+		return domain.ErrInvalidAPIKey
+	case strings.Contains(msg, "request limit"):
+		return domain.ErrRateLimited
+	case strings.Contains(msg, "too many results"):
+		return domain.ErrTooManyResults
+	default:
+		return errors.New("omdb: " + s.Error)
+	}
+}
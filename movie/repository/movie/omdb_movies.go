@@ -5,67 +5,155 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/movie/repository"
+	"github.com/bxcodec/go-clean-arch/tracing"
 )
 
 type omdbAPIRepository struct {
-	APIKey string
+	Client  *http.Client
+	BaseURL string
+	APIKey  string
 }
 
 const (
-	omdbBaseURL = "http://www.omdbapi.com/"
+	omdbBaseURL  = "http://www.omdbapi.com/"
+	omdbPageSize = 10
 )
 
-// NewMysqlMovieRepository will create an object that represent the movie.Repository interface
-func NewMysqlMovieRepository(APIKey string) domain.MovieRepository {
-	return &omdbAPIRepository{APIKey}
+type omdbSearchResponse struct {
+	domain.SearchResult
+	omdbStatus
+}
+
+// omdbMovieResponse decodes the DVD and Released dates under their OMDb names, domain.Movies serving them under
+// the names of the legacy /movies shape
+type omdbMovieResponse struct {
+	domain.Movies
+	DVD      string `json:"DVD"`
+	Released string `json:"Released"`
+	omdbStatus
 }
 
-func (m *omdbAPIRepository) Fetch(ctx context.Context, page string, searchword string) (res []domain.Movies, nextCursor string, err error) {
-	var client = &http.Client{}
-	var movies domain.SearchResult
+// NewOMDbMovieRepository will create an implementation of domain.MovieRepository backed by the OMDb API.
+// A nil client falls back to http.DefaultClient and an empty baseURL to the public OMDb endpoint
+func NewOMDbMovieRepository(client *http.Client, baseURL string, APIKey string) domain.MovieRepository {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if baseURL == "" {
+		baseURL = omdbBaseURL
+	}
+
+	return &omdbAPIRepository{
+		Client:  client,
+		BaseURL: baseURL,
+		APIKey:  APIKey,
+	}
+}
 
-	url := fmt.Sprintf("%s?apikey=%s&s=%s&page=%s", omdbBaseURL, m.APIKey, searchword, page)
-	request, err := http.NewRequest("GET", url, nil)
+func (m *omdbAPIRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	ctx, span := tracing.Start(ctx, "omdbAPIRepository.Fetch")
+	defer tracing.Finish(span, &err)
+
+	var movies omdbSearchResponse
+
+	page, err := repository.DecodeCursor(criteria.Cursor)
 	if err != nil {
-		return
+		return res, domain.ErrBadParamInput
 	}
 
-	response, err := client.Do(request)
+	params := url.Values{}
+	params.Set("s", criteria.Searchword)
+	if criteria.Year != 0 {
+		params.Set("y", strconv.Itoa(criteria.Year))
+	}
+	if criteria.Type != "" {
+		params.Set("type", string(criteria.Type))
+	}
+	params.Set("page", strconv.Itoa(page))
+	err = m.get(ctx, params, &movies)
 	if err != nil {
 		return
 	}
-	defer response.Body.Close()
+	if err = movies.err(); err != nil {
+		return
+	}
+
+	total, _ := strconv.Atoi(movies.TotalResults)
+	res = domain.MoviePage{
+		Movies: movies.Search,
+		Total:  total,
+	}
+	if page*omdbPageSize < total {
+		res.NextCursor = repository.EncodeCursor(page + 1)
+	}
+	if page > 1 {
+		res.PrevCursor = repository.EncodeCursor(page - 1)
+	}
+
+	return res, nil
+}
 
-	err = json.NewDecoder(response.Body).Decode(&movies)
+func (m *omdbAPIRepository) GetByID(ctx context.Context, imdbID string, plot domain.PlotLength) (res domain.Movies, err error) {
+	ctx, span := tracing.Start(ctx, "omdbAPIRepository.GetByID")
+	defer tracing.Finish(span, &err)
+
+	var movies omdbMovieResponse
+
+	params := url.Values{}
+	params.Set("i", imdbID)
+	if plot == "" {
+		plot = domain.PlotFull
+	}
+	params.Set("plot", string(plot))
+	err = m.get(ctx, params, &movies)
 	if err != nil {
 		return
 	}
+	if err = movies.err(); err != nil {
+		return
+	}
 
-	return movies.Search, "1", err
+	res = movies.Movies
+	res.DVD, res.Released = movies.DVD, movies.Released
+	return res, nil
 }
 
-func (m *omdbAPIRepository) GetByID(ctx context.Context, imdbID string) (res domain.Movies, err error) {
-	var client = &http.Client{}
-	var movies domain.Movies
+// get will call the OMDb endpoint with the given query params and decode the payload into dest.
+// The call is traced as a client span propagated to OMDb through the traceparent header
+func (m *omdbAPIRepository) get(ctx context.Context, params url.Values, dest interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "omdb GET", trace.WithSpanKind(trace.SpanKindClient))
+	defer tracing.Finish(span, &err)
+	// the api key is left out of the traced url
+	span.SetAttributes(
+		attribute.String("http.method", http.MethodGet),
+		attribute.String("http.url", m.BaseURL+"?"+params.Encode()),
+	)
 
-	url := fmt.Sprintf("%s?apikey=%s&i=%s&plot=full", omdbBaseURL, m.APIKey, imdbID)
-	request, err := http.NewRequest("GET", url, nil)
+	params.Set("apikey", m.APIKey)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, m.BaseURL+"?"+params.Encode(), nil)
 	if err != nil {
 		return
 	}
+	tracing.Inject(ctx, request.Header)
 
-	response, err := client.Do(request)
+	response, err := m.Client.Do(request)
 	if err != nil {
 		return
 	}
 	defer response.Body.Close()
+	span.SetAttributes(attribute.Int("http.status_code", response.StatusCode))
 
-	err = json.NewDecoder(response.Body).Decode(&movies)
-	if err != nil {
-		return
+	if response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("omdb: unexpected status %d", response.StatusCode)
 	}
 
-	return movies, err
+	return json.NewDecoder(response.Body).Decode(dest)
 }
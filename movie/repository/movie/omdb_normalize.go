@@ -0,0 +1,144 @@
+package movie
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// omdbDateLayout is the layout of the Released and DVD dates of OMDb, e.g. 14 Oct 1994
+const omdbDateLayout = "02 Jan 2006"
+
+// Normalize will parse the raw OMDb fields into a domain.Movie, tolerating "N/A" and values that don't parse by
+// leaving the matching field zero. The other providers convert their movies to the OMDb formats, so it normalizes
+// merged movies too
+func Normalize(m domain.Movies) domain.Movie {
+	res := domain.Movie{
+		ImdbID:     m.ID,
+		Title:      text(m.Title),
+		Type:       domain.MovieType(strings.ToLower(text(m.Type))),
+		Rated:      text(m.Rated),
+		Released:   parseDate(m.Released),
+		DVD:        parseDate(m.DVD),
+		Runtime:    parseRuntime(m.Runtime),
+		Genres:     parseList(m.Genre),
+		Directors:  parseList(m.Director),
+		Writers:    parseList(m.Writer),
+		Actors:     parseList(m.Actors),
+		Languages:  parseList(m.Language),
+		Countries:  parseList(m.Country),
+		Plot:       text(m.Plot),
+		Awards:     text(m.Awards),
+		Poster:     text(m.Poster),
+		Backdrop:   text(m.Backdrop),
+		Keywords:   parseList(m.Keywords),
+		Metascore:  parseInt(m.Metascore),
+		ImdbRating: parseFloat(m.ImdbRating),
+		ImdbVotes:  parseInt(m.ImdbVotes),
+		Sources:    m.Sources,
+	}
+	res.Year, res.EndYear = parseYears(m.Year)
+
+	for _, r := range m.Ratings {
+		if text(r.Value) == "" {
+			continue
+		}
+		res.Ratings = append(res.Ratings, domain.MovieRating{
+			Source: r.Source,
+			Value:  r.Value,
+			Score:  parseScore(r.Value),
+		})
+	}
+	return res
+}
+
+// text returns s trimmed, or empty when OMDb has no value for it
+func text(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "N/A") {
+		return ""
+	}
+	return s
+}
+
+// parseYears parses a single year like 1994 or the range of a series like 2005–2008, the end year being zero
+// while the series is still running
+func parseYears(s string) (int, int) {
+	parts := strings.FieldsFunc(text(s), func(r rune) bool {
+		return r == '–' || r == '-'
+	})
+	if len(parts) == 0 {
+		return 0, 0
+	}
+
+	start := parseInt(parts[0])
+	if len(parts) == 1 {
+		return start, 0
+	}
+	return start, parseInt(parts[1])
+}
+
+func parseDate(s string) *time.Time {
+	t, err := time.Parse(omdbDateLayout, text(s))
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// parseRuntime parses runtimes like 142 min, OMDb doesn't report them in any other unit
+func parseRuntime(s string) time.Duration {
+	fields := strings.Fields(text(s))
+	if len(fields) == 0 {
+		return 0
+	}
+	return time.Duration(parseInt(fields[0])) * time.Minute
+}
+
+// parseList splits the comma separated values OMDb joins genres, people, languages and countries with
+func parseList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(text(s), ",") {
+		if item = text(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseInt parses integers written with thousands separators like 2,345,678
+func parseInt(s string) int {
+	n, err := strconv.Atoi(strings.Replace(text(s), ",", "", -1))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func parseFloat(s string) float64 {
+	f, err := strconv.ParseFloat(text(s), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// parseScore brings the ratings OMDb reports as 9.3/10, 91% or 74/100 to a 0 to 100 scale
+func parseScore(s string) float64 {
+	s = text(s)
+	if strings.HasSuffix(s, "%") {
+		return parseFloat(strings.TrimSuffix(s, "%"))
+	}
+
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	max := parseFloat(parts[1])
+	if max == 0 {
+		return 0
+	}
+	return parseFloat(parts[0]) * 100 / max
+}
@@ -0,0 +1,91 @@
+package movie_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	movieRepo "github.com/bxcodec/go-clean-arch/movie/repository/movie"
+)
+
+func TestNormalize(t *testing.T) {
+	m := movieRepo.Normalize(domain.Movies{
+		ID:       "tt0111161",
+		Title:    "The Shawshank Redemption",
+		Year:     "1994",
+		Rated:    "R",
+		Released: "14 Oct 1994",
+		Runtime:  "142 min",
+		Genre:    "Drama",
+		Director: "Frank Darabont",
+		Writer:   "Stephen King, Frank Darabont",
+		Actors:   "Tim Robbins, Morgan Freeman, Bob Gunton",
+		Plot:     "Two imprisoned men bond over a number of years.",
+		Language: "English",
+		Country:  "United States",
+		Awards:   "N/A",
+		Poster:   "N/A",
+		Ratings: []domain.Rating{
+			{Source: "Internet Movie Database", Value: "9.3/10"},
+			{Source: "Rotten Tomatoes", Value: "91%"},
+			{Source: "Metacritic", Value: "82/100"},
+			{Source: "Nobody", Value: "N/A"},
+		},
+		Metascore:  "82",
+		ImdbRating: "9.3",
+		ImdbVotes:  "2,713,581",
+		Type:       "movie",
+		DVD:        "21 Dec 1999",
+	})
+	assert.Equal(t, "tt0111161", m.ImdbID)
+	assert.Equal(t, domain.MovieTypeMovie, m.Type)
+	assert.Equal(t, 1994, m.Year)
+	assert.Zero(t, m.EndYear)
+	assert.Equal(t, 142*time.Minute, m.Runtime)
+	require.NotNil(t, m.Released)
+	assert.Equal(t, time.Date(1994, time.October, 14, 0, 0, 0, 0, time.UTC), *m.Released)
+	require.NotNil(t, m.DVD)
+	assert.Equal(t, time.Date(1999, time.December, 21, 0, 0, 0, 0, time.UTC), *m.DVD)
+	assert.Equal(t, []string{"Stephen King", "Frank Darabont"}, m.Writers)
+	assert.Equal(t, []string{"Tim Robbins", "Morgan Freeman", "Bob Gunton"}, m.Actors)
+	assert.Equal(t, []string{"Drama"}, m.Genres)
+	assert.Empty(t, m.Awards)
+	assert.Empty(t, m.Poster)
+	assert.Equal(t, 82, m.Metascore)
+	assert.Equal(t, 9.3, m.ImdbRating)
+	assert.Equal(t, 2713581, m.ImdbVotes)
+	require.Len(t, m.Ratings, 3)
+	assert.InDelta(t, 93, m.Ratings[0].Score, 0.001)
+	assert.Equal(t, 91.0, m.Ratings[1].Score)
+	assert.Equal(t, 82.0, m.Ratings[2].Score)
+}
+
+func TestNormalizeNotAvailable(t *testing.T) {
+	m := movieRepo.Normalize(domain.Movies{
+		ID:         "tt0000001",
+		Year:       "N/A",
+		Released:   "N/A",
+		Runtime:    "N/A",
+		Genre:      "N/A",
+		ImdbRating: "N/A",
+		ImdbVotes:  "N/A",
+	})
+
+	assert.Equal(t, domain.Movie{ImdbID: "tt0000001"}, m)
+}
+
+func TestNormalizeSeriesYears(t *testing.T) {
+	tests := map[string][2]int{
+		"2005–2008": {2005, 2008},
+		"2011–":     {2011, 0},
+		"2011-2019": {2011, 2019},
+	}
+	for year, expected := range tests {
+		m := movieRepo.Normalize(domain.Movies{Year: year})
+		assert.Equal(t, expected[0], m.Year, year)
+		assert.Equal(t, expected[1], m.EndYear, year)
+	}
+}
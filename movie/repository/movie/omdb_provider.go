@@ -0,0 +1,22 @@
+package movie
+
+import (
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// ProviderName is the name the OMDb provider is known by in the precedence and the provenance of merged movies
+const ProviderName = "omdb"
+
+type omdbProvider struct {
+	domain.MovieRepository
+}
+
+// NewOMDbProvider will create the domain.MovieProvider of OMDb on top of the given repository, usually the
+// OMDb repository wrapped in its rate limiting and resilience decorators. The provider searches through it too
+func NewOMDbProvider(repo domain.MovieRepository) domain.MovieProvider {
+	return &omdbProvider{repo}
+}
+
+func (p *omdbProvider) Name() string {
+	return ProviderName
+}
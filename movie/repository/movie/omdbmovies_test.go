@@ -2,163 +2,193 @@ package movie_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
-	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/movie/repository"
+	movieRepo "github.com/bxcodec/go-clean-arch/movie/repository/movie"
+	"github.com/bxcodec/go-clean-arch/omdbfake"
+	"github.com/bxcodec/go-clean-arch/tracing"
 )
 
-func TestFetch(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	mockMovies := []domain.Movie{
-		domain.Movie{
-			ID: 1, Title: "title 1", Content: "content 1",
-			Logmovie: domain.Logmovie{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
-		},
-		domain.Movie{
-			ID: 2, Title: "title 2", Content: "content 2",
-			Logmovie: domain.Logmovie{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
-		},
-	}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "logmovie_id", "updated_at", "created_at"}).
-		AddRow(mockMovies[0].ID, mockMovies[0].Title, mockMovies[0].Content,
-			mockMovies[0].Logmovie.ID, mockMovies[0].UpdatedAt, mockMovies[0].CreatedAt).
-		AddRow(mockMovies[1].ID, mockMovies[1].Title, mockMovies[1].Content,
-			mockMovies[1].Logmovie.ID, mockMovies[1].UpdatedAt, mockMovies[1].CreatedAt)
-
-	query := "SELECT id,title,content, logmovie_id, updated_at, created_at FROM movie WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
-
-	mock.ExpectQuery(query).WillReturnRows(rows)
-	a := movieMysqlRepo.NewMysqlMovieRepository(db)
-	cursor := repository.EncodeCursor(mockMovies[1].CreatedAt)
-	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
-	assert.NotEmpty(t, nextCursor)
-	assert.NoError(t, err)
-	assert.Len(t, list, 2)
+func newOMDbFake(t *testing.T, opts omdbfake.Options) (domain.MovieRepository, *omdbfake.Server) {
+	opts.APIKey = "secret"
+	srv, fake := omdbfake.NewTestServer(opts)
+	t.Cleanup(srv.Close)
+	return movieRepo.NewOMDbMovieRepository(srv.Client(), srv.URL, "secret"), fake
 }
 
-func TestGetByID(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
+func TestFetch(t *testing.T) {
+	a, fake := newOMDbFake(t, omdbfake.Options{})
+
+	page, err := a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+	require.NoError(t, err)
+	assert.Len(t, page.Movies, 10)
+	assert.Equal(t, "tt0372784", page.Movies[0].ID)
+	assert.Equal(t, 12, page.Total)
+	assert.Equal(t, repository.EncodeCursor(2), page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+
+	page, err = a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Len(t, page.Movies, 2)
+	assert.Empty(t, page.NextCursor)
+	assert.Equal(t, repository.EncodeCursor(1), page.PrevCursor)
+
+	requests := fake.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "Batman", requests[1].Get("s"))
+	assert.Equal(t, "2", requests[1].Get("page"))
+	assert.Equal(t, "secret", requests[1].Get("apikey"))
+	assert.Empty(t, requests[1].Get("y"))
+	assert.Empty(t, requests[1].Get("type"))
+}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "logmovie_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+func TestFetchEscaping(t *testing.T) {
+	a, fake := newOMDbFake(t, omdbfake.Options{})
 
-	query := "SELECT id,title,content, logmovie_id, updated_at, created_at FROM movie WHERE ID = \\?"
+	page, err := a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman & Robin"})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 1)
+	assert.Equal(t, "tt0118688", page.Movies[0].ID)
+	assert.Equal(t, "Batman & Robin", fake.Requests()[0].Get("s"))
+}
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
-	a := movieMysqlRepo.NewMysqlMovieRepository(db)
+func TestFetchFilters(t *testing.T) {
+	a, fake := newOMDbFake(t, omdbfake.Options{})
 
-	num := int64(5)
-	anMovie, err := a.GetByID(context.TODO(), num)
-	assert.NoError(t, err)
-	assert.NotNil(t, anMovie)
-}
+	page, err := a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Year: 2005, Type: domain.MovieTypeMovie})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 1)
+	assert.Equal(t, "Batman Begins", page.Movies[0].Title)
+	assert.Equal(t, "2005", fake.Requests()[0].Get("y"))
+	assert.Equal(t, "movie", fake.Requests()[0].Get("type"))
 
-func TestStore(t *testing.T) {
-	now := time.Now()
-	ar := &domain.Movie{
-		Title:     "Judul",
-		Content:   "Content",
-		CreatedAt: now,
-		UpdatedAt: now,
-		Logmovie: domain.Logmovie{
-			ID:   1,
-			Name: "Iman Tumorang",
-		},
-	}
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	query := "INSERT  movie SET title=\\? , content=\\? , logmovie_id=\\?, updated_at=\\? , created_at=\\?"
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Logmovie.ID, ar.CreatedAt, ar.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
-
-	a := movieMysqlRepo.NewMysqlMovieRepository(db)
-
-	err = a.Store(context.TODO(), ar)
-	assert.NoError(t, err)
-	assert.Equal(t, int64(12), ar.ID)
+	page, err = a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Type: domain.MovieTypeSeries})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 1)
+	assert.Equal(t, "tt0103359", page.Movies[0].ID)
 }
 
-func TestGetByTitle(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
+func TestFetchError(t *testing.T) {
+	a, _ := newOMDbFake(t, omdbfake.Options{})
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "logmovie_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	_, err := a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Casablanca"})
+	assert.Equal(t, domain.ErrNotFound, err)
 
-	query := "SELECT id,title,content, logmovie_id, updated_at, created_at FROM movie WHERE title = \\?"
+	_, err = a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "a"})
+	assert.Equal(t, domain.ErrTooManyResults, err)
+}
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
-	a := movieMysqlRepo.NewMysqlMovieRepository(db)
+func TestFetchInvalidCursor(t *testing.T) {
+	a := movieRepo.NewOMDbMovieRepository(nil, "http://127.0.0.1:0/", "secret")
+	_, err := a.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Cursor: "not-a-cursor"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
 
-	title := "title 1"
-	anMovie, err := a.GetByTitle(context.TODO(), title)
-	assert.NoError(t, err)
-	assert.NotNil(t, anMovie)
+func TestGetByID(t *testing.T) {
+	a, fake := newOMDbFake(t, omdbfake.Options{})
+
+	anMovie, err := a.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	require.NoError(t, err)
+	assert.Equal(t, "The Shawshank Redemption", anMovie.Title)
+	assert.Equal(t, "9.3", anMovie.ImdbRating)
+	assert.Equal(t, "21 Dec 1999", anMovie.DVD)
+	assert.Len(t, anMovie.Ratings, 3)
+	assert.Contains(t, anMovie.Plot, "redemption through basic compassion")
+	assert.Equal(t, "tt0111161", fake.Requests()[0].Get("i"))
+	assert.Equal(t, "full", fake.Requests()[0].Get("plot"))
 }
 
-func TestDelete(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
+func TestGetByIDShortPlot(t *testing.T) {
+	a, fake := newOMDbFake(t, omdbfake.Options{})
 
-	query := "DELETE FROM movie WHERE id = \\?"
+	anMovie, err := a.GetByID(context.TODO(), "tt0111161", domain.PlotShort)
+	require.NoError(t, err)
+	assert.Equal(t, "Two imprisoned men bond over a number of years.", anMovie.Plot)
+	assert.Equal(t, "short", fake.Requests()[0].Get("plot"))
+}
+
+func TestGetByIDError(t *testing.T) {
+	t.Run("not-found", func(t *testing.T) {
+		a, _ := newOMDbFake(t, omdbfake.Options{})
+		_, err := a.GetByID(context.TODO(), "tt9999999", domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("incorrect-id", func(t *testing.T) {
+		a, _ := newOMDbFake(t, omdbfake.Options{})
+		_, err := a.GetByID(context.TODO(), "not-an-id", domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("invalid-key", func(t *testing.T) {
+		srv, _ := omdbfake.NewTestServer(omdbfake.Options{APIKey: "secret"})
+		defer srv.Close()
+
+		a := movieRepo.NewOMDbMovieRepository(srv.Client(), srv.URL, "wrong")
+		_, err := a.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.Equal(t, domain.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		a, _ := newOMDbFake(t, omdbfake.Options{RequestLimit: 1})
+		_, err := a.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		require.NoError(t, err)
+		_, err = a.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.Equal(t, domain.ErrRateLimited, err)
+	})
+
+	t.Run("server-error", func(t *testing.T) {
+		a, fake := newOMDbFake(t, omdbfake.Options{})
+		fake.FailNext(1, http.StatusServiceUnavailable)
+		_, err := a.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.EqualError(t, err, "omdb: unexpected status 503")
+
+		_, err = a.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.NoError(t, err)
+	})
+}
 
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+func TestGetByIDCanceled(t *testing.T) {
+	a, _ := newOMDbFake(t, omdbfake.Options{Latency: time.Minute})
 
-	a := movieMysqlRepo.NewMysqlMovieRepository(db)
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
 
-	num := int64(12)
-	err = a.Delete(context.TODO(), num)
-	assert.NoError(t, err)
+	start := time.Now()
+	_, err := a.GetByID(ctx, "tt0111161", domain.PlotFull)
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
 }
 
-func TestUpdate(t *testing.T) {
-	now := time.Now()
-	ar := &domain.Movie{
-		ID:        12,
-		Title:     "Judul",
-		Content:   "Content",
-		CreatedAt: now,
-		UpdatedAt: now,
-		Logmovie: domain.Logmovie{
-			ID:   1,
-			Name: "Iman Tumorang",
-		},
-	}
-
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	query := "UPDATE movie set title=\\?, content=\\?, logmovie_id=\\?, updated_at=\\? WHERE ID = \\?"
-
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Logmovie.ID, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
-
-	a := movieMysqlRepo.NewMysqlMovieRepository(db)
-
-	err = a.Update(context.TODO(), ar)
-	assert.NoError(t, err)
+func TestGetByIDTraceparent(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	ctx, span := tp.Tracer("test").Start(context.TODO(), "GET /movies/:id", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	fake := omdbfake.New(omdbfake.Options{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc := trace.SpanContextFromContext(tracing.Extract(context.TODO(), r.Header))
+		assert.True(t, sc.IsValid())
+		assert.Equal(t, span.SpanContext().TraceID(), sc.TraceID())
+		assert.NotEqual(t, span.SpanContext().SpanID(), sc.SpanID())
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	a := movieRepo.NewOMDbMovieRepository(srv.Client(), srv.URL, "secret")
+	_, err := a.GetByID(ctx, "tt0111161", domain.PlotFull)
+	require.NoError(t, err)
 }
@@ -0,0 +1,302 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
+	"github.com/bxcodec/go-clean-arch/movie/repository"
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+// pageSize is the number of movies per page of a search, the same as OMDb
+const pageSize = 10
+
+const selectMovie = `SELECT imdbID, title, year, rated, released, runtime, genre, director, writer, actors,
+	plot_short, plot_full, language, country, awards, poster, backdrop, keywords, type, metascore, imdbRating,
+	imdbVotes, dvd, sources, updated_at FROM movie_catalog WHERE imdbID = ?`
+
+type mysqlMovieRepo struct {
+	DB  *sql.DB
+	TTL time.Duration
+	now func() time.Time
+}
+
+// NewMysqlMovieRepository will create an implementation of domain.MovieCatalogRepository storing the full details
+// of the movies, ratings included, in MySQL. A movie stored longer than ttl ago is a miss so it gets looked up
+// again, a zero ttl keeps the movies for good
+func NewMysqlMovieRepository(db *sql.DB, ttl time.Duration) domain.MovieCatalogRepository {
+	return &mysqlMovieRepo{
+		DB:  db,
+		TTL: ttl,
+		now: time.Now,
+	}
+}
+
+// startSpan will trace the given query of the movie catalog as a client span of the request
+func startSpan(ctx context.Context, method, query string) (context.Context, trace.Span) {
+	ctx, span := tracing.Start(ctx, "mysqlMovieRepo."+method, trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("db.system", "mysql"),
+		attribute.String("db.statement", query),
+	)
+	return ctx, span
+}
+
+// Fetch will run a full-text search of the catalog over the titles, plots, actors and directors, answering with the
+// same summaries and pages as an OMDb search. The movies are ranked by relevance, a match in the title counting twice
+func (mm *mysqlMovieRepo) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	page, err := repository.DecodeCursor(criteria.Cursor)
+	if err != nil {
+		return res, domain.ErrBadParamInput
+	}
+
+	where, args := searchFilter(criteria)
+	query := `SELECT imdbID, title, year, type, poster, ` + relevance + ` AS relevance FROM movie_catalog WHERE ` +
+		where + ` ORDER BY relevance DESC, imdbID LIMIT ? OFFSET ?`
+	ctx, span := startSpan(ctx, "Fetch", query)
+	defer tracing.Finish(span, &err)
+
+	err = mm.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM movie_catalog WHERE `+where, args...).Scan(&res.Total)
+	if err != nil {
+		return domain.MoviePage{}, err
+	}
+	if res.Total == 0 {
+		return domain.MoviePage{}, domain.ErrNotFound
+	}
+
+	queryArgs := append([]interface{}{criteria.Searchword, criteria.Searchword}, args...)
+	rows, err := mm.DB.QueryContext(ctx, query, append(queryArgs, pageSize, (page-1)*pageSize)...)
+	if err != nil {
+		return domain.MoviePage{}, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logger.FromContext(ctx).Error(errRow)
+		}
+	}()
+
+	res.Movies = make([]domain.Movies, 0, pageSize)
+	for rows.Next() {
+		m := domain.Movies{}
+		var score float64
+		if err = rows.Scan(&m.ID, &m.Title, &m.Year, &m.Type, &m.Poster, &score); err != nil {
+			return domain.MoviePage{}, err
+		}
+		res.Movies = append(res.Movies, m)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.MoviePage{}, err
+	}
+
+	if page*pageSize < res.Total {
+		res.NextCursor = repository.EncodeCursor(page + 1)
+	}
+	if page > 1 {
+		res.PrevCursor = repository.EncodeCursor(page - 1)
+	}
+	return res, nil
+}
+
+// searchMatch matches the searchword against the ft_search full-text index
+const searchMatch = `MATCH (title, director, actors, plot_short, plot_full) AGAINST (? IN NATURAL LANGUAGE MODE)`
+
+// relevance ranks a movie, the title being matched again against the ft_title index so it weighs more
+const relevance = `MATCH (title) AGAINST (? IN NATURAL LANGUAGE MODE) * 2 + ` + searchMatch
+
+// yearMatch matches the movies released in a year and the series running that year. Series are stored with
+// the range of years they ran, e.g. 2005–2008, or 2005– while still running, so the year is compared to both
+// ends of the range
+const yearMatch = `LEFT(year, 4) <= ? AND CASE CHAR_LENGTH(year) WHEN 4 THEN year WHEN 5 THEN '9999' ` +
+	`ELSE SUBSTRING(year, 6, 4) END >= ?`
+
+// searchFilter builds the WHERE clause of a search
+func searchFilter(criteria domain.SearchCriteria) (string, []interface{}) {
+	conditions := []string{searchMatch}
+	args := []interface{}{criteria.Searchword}
+	if criteria.Year != 0 {
+		year := strconv.Itoa(criteria.Year)
+		conditions = append(conditions, yearMatch)
+		args = append(args, year, year)
+	}
+	if criteria.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, string(criteria.Type))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetByID will return the stored movie, a movie stored without the plot of the requested length or stored longer
+// than the TTL ago is a miss
+func (mm *mysqlMovieRepo) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	ctx, span := startSpan(ctx, "GetByID", selectMovie)
+	defer tracing.Finish(span, &err)
+
+	var plotShort, plotFull, sources sql.NullString
+	var updatedAt time.Time
+	err = mm.DB.QueryRowContext(ctx, selectMovie, id).Scan(
+		&res.ID,
+		&res.Title,
+		&res.Year,
+		&res.Rated,
+		&res.Released,
+		&res.Runtime,
+		&res.Genre,
+		&res.Director,
+		&res.Writer,
+		&res.Actors,
+		&plotShort,
+		&plotFull,
+		&res.Language,
+		&res.Country,
+		&res.Awards,
+		&res.Poster,
+		&res.Backdrop,
+		&res.Keywords,
+		&res.Type,
+		&res.Metascore,
+		&res.ImdbRating,
+		&res.ImdbVotes,
+		&res.DVD,
+		&sources,
+		&updatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.Movies{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Movies{}, err
+	}
+
+	if mm.TTL > 0 && mm.now().Sub(updatedAt) > mm.TTL {
+		span.SetAttributes(attribute.Bool("catalog.stale", true))
+		return domain.Movies{}, domain.ErrNotFound
+	}
+
+	stored := plotFull
+	if plot == domain.PlotShort {
+		stored = plotShort
+	}
+	if !stored.Valid {
+		return domain.Movies{}, domain.ErrNotFound
+	}
+	res.Plot = stored.String
+
+	if sources.Valid {
+		if err = json.Unmarshal([]byte(sources.String), &res.Sources); err != nil {
+			return domain.Movies{}, err
+		}
+	}
+
+	res.Ratings, err = mm.ratings(ctx, id)
+	if err != nil {
+		return domain.Movies{}, err
+	}
+	return res, nil
+}
+
+func (mm *mysqlMovieRepo) ratings(ctx context.Context, id string) (res []domain.Rating, err error) {
+	query := `SELECT source, value FROM movie_catalog_ratings WHERE imdbID = ? ORDER BY position`
+	rows, err := mm.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logger.FromContext(ctx).Error(errRow)
+		}
+	}()
+
+	for rows.Next() {
+		r := domain.Rating{}
+		if err = rows.Scan(&r.Source, &r.Value); err != nil {
+			return nil, err
+		}
+		res = append(res, r)
+	}
+	return res, rows.Err()
+}
+
+// Store will upsert the movie along with its ratings in a single transaction. The plot is stored as the plot of
+// the given length, the plot of the other length already stored is kept
+func (mm *mysqlMovieRepo) Store(ctx context.Context, m *domain.Movies, plot domain.PlotLength) (err error) {
+	query := `INSERT INTO movie_catalog (imdbID, title, year, rated, released, runtime, genre, director, writer,
+		actors, plot_short, plot_full, language, country, awards, poster, backdrop, keywords, type, metascore,
+		imdbRating, imdbVotes, dvd, sources, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE title=VALUES(title), year=VALUES(year), rated=VALUES(rated),
+		released=VALUES(released), runtime=VALUES(runtime), genre=VALUES(genre), director=VALUES(director),
+		writer=VALUES(writer), actors=VALUES(actors), plot_short=COALESCE(VALUES(plot_short), plot_short),
+		plot_full=COALESCE(VALUES(plot_full), plot_full), language=VALUES(language), country=VALUES(country),
+		awards=VALUES(awards), poster=VALUES(poster), backdrop=VALUES(backdrop), keywords=VALUES(keywords),
+		type=VALUES(type), metascore=VALUES(metascore), imdbRating=VALUES(imdbRating), imdbVotes=VALUES(imdbVotes),
+		dvd=VALUES(dvd), sources=VALUES(sources), updated_at=VALUES(updated_at)`
+	ctx, span := startSpan(ctx, "Store", query)
+	defer tracing.Finish(span, &err)
+
+	var plotShort, plotFull interface{}
+	if plot == domain.PlotShort {
+		plotShort = m.Plot
+	} else {
+		plotFull = m.Plot
+	}
+
+	var sources interface{}
+	if len(m.Sources) > 0 {
+		byt, errEncode := json.Marshal(m.Sources)
+		if errEncode != nil {
+			return errEncode
+		}
+		sources = string(byt)
+	}
+
+	tx, err := mm.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				logger.FromContext(ctx).Error(errRollback)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, query, m.ID, m.Title, m.Year, m.Rated, m.Released, m.Runtime, m.Genre, m.Director,
+		m.Writer, m.Actors, plotShort, plotFull, m.Language, m.Country, m.Awards, m.Poster, m.Backdrop, m.Keywords,
+		m.Type, m.Metascore, m.ImdbRating, m.ImdbVotes, m.DVD, sources, mm.now())
+	if err != nil {
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM movie_catalog_ratings WHERE imdbID = ?`, m.ID)
+	if err != nil {
+		return
+	}
+
+	if len(m.Ratings) > 0 {
+		placeholders := make([]string, 0, len(m.Ratings))
+		args := make([]interface{}, 0, len(m.Ratings)*4)
+		for i, r := range m.Ratings {
+			placeholders = append(placeholders, "(?, ?, ?, ?)")
+			args = append(args, m.ID, i, r.Source, r.Value)
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO movie_catalog_ratings (imdbID, position, source, value) VALUES `+
+			strings.Join(placeholders, ", "), args...)
+		if err != nil {
+			return
+		}
+	}
+
+	return tx.Commit()
+}
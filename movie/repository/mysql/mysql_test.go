@@ -0,0 +1,192 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/movie/repository"
+	movieMysqlRepo "github.com/bxcodec/go-clean-arch/movie/repository/mysql"
+)
+
+var columns = []string{"imdbID", "title", "year", "rated", "released", "runtime", "genre", "director", "writer",
+	"actors", "plot_short", "plot_full", "language", "country", "awards", "poster", "backdrop", "keywords", "type",
+	"metascore", "imdbRating", "imdbVotes", "dvd", "sources", "updated_at"}
+
+const selectQuery = "SELECT imdbID, title, year, rated, released, runtime, genre, director, writer, actors, " +
+	"plot_short, plot_full, language, country, awards, poster, backdrop, keywords, type, metascore, imdbRating, " +
+	"imdbVotes, dvd, sources, updated_at FROM movie_catalog WHERE imdbID = \\?"
+
+func newMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	return db, mock
+}
+
+// storedSince matches the updated_at written by Store, taken from the clock of the repository
+type storedSince time.Time
+
+func (s storedSince) Match(v driver.Value) bool {
+	updatedAt, ok := v.(time.Time)
+	return ok && !updatedAt.Before(time.Time(s)) && !updatedAt.After(time.Now())
+}
+
+func movieRow(plotShort, plotFull interface{}) *sqlmock.Rows {
+	return movieRowAt(time.Now(), plotShort, plotFull)
+}
+
+func movieRowAt(updatedAt time.Time, plotShort, plotFull interface{}) *sqlmock.Rows {
+	return sqlmock.NewRows(columns).AddRow("tt0111161", "The Shawshank Redemption", "1994", "R", "14 Oct 1994",
+		"142 min", "Drama", "Frank Darabont", "Stephen King, Frank Darabont", "Tim Robbins, Morgan Freeman",
+		plotShort, plotFull, "English", "United States", "N/A", "N/A", "/backdrop.jpg", "prison, friendship", "movie",
+		"82", "9.3", "2,713,581", "21 Dec 1999", `{"Backdrop":"tmdb","Title":"omdb"}`, updatedAt)
+}
+
+func TestGetByID(t *testing.T) {
+	db, mock := newMock(t)
+	mock.ExpectQuery(selectQuery).WithArgs("tt0111161").WillReturnRows(movieRow(nil, "Two imprisoned men bond."))
+	mock.ExpectQuery("SELECT source, value FROM movie_catalog_ratings WHERE imdbID = \\? ORDER BY position").
+		WithArgs("tt0111161").
+		WillReturnRows(sqlmock.NewRows([]string{"source", "value"}).
+			AddRow("Internet Movie Database", "9.3/10").
+			AddRow("Rotten Tomatoes", "91%"))
+
+	r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+	m, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	require.NoError(t, err)
+	assert.Equal(t, "The Shawshank Redemption", m.Title)
+	assert.Equal(t, "Two imprisoned men bond.", m.Plot)
+	assert.Equal(t, "21 Dec 1999", m.DVD)
+	assert.Equal(t, "prison, friendship", m.Keywords)
+	assert.Equal(t, map[string]string{"Backdrop": "tmdb", "Title": "omdb"}, m.Sources)
+	assert.Equal(t, []domain.Rating{
+		{Source: "Internet Movie Database", Value: "9.3/10"},
+		{Source: "Rotten Tomatoes", Value: "91%"},
+	}, m.Ratings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByIDMiss(t *testing.T) {
+	t.Run("not-stored", func(t *testing.T) {
+		db, mock := newMock(t)
+		mock.ExpectQuery(selectQuery).WithArgs("tt0000000").WillReturnRows(sqlmock.NewRows(columns))
+
+		r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+		_, err := r.GetByID(context.TODO(), "tt0000000", domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("plot-not-stored", func(t *testing.T) {
+		db, mock := newMock(t)
+		mock.ExpectQuery(selectQuery).WithArgs("tt0111161").WillReturnRows(movieRow(nil, "Two imprisoned men bond."))
+
+		r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+		_, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotShort)
+		assert.Equal(t, domain.ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		db, mock := newMock(t)
+		mock.ExpectQuery(selectQuery).WithArgs("tt0111161").
+			WillReturnRows(movieRowAt(time.Now().Add(-2*time.Hour), nil, "Two imprisoned men bond."))
+
+		r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+		_, err := r.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStore(t *testing.T) {
+	db, mock := newMock(t)
+	m := &domain.Movies{
+		ID:      "tt0111161",
+		Title:   "The Shawshank Redemption",
+		Plot:    "Two imprisoned men bond.",
+		Sources: map[string]string{"Title": "omdb"},
+		Ratings: []domain.Rating{{Source: "Internet Movie Database", Value: "9.3/10"}, {Source: "Metacritic", Value: "82/100"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO movie_catalog \\(imdbID").
+		WithArgs(m.ID, m.Title, "", "", "", "", "", "", "", "", m.Plot, nil, "", "", "", "", "", "", "", "", "", "", "",
+			`{"Title":"omdb"}`, storedSince(time.Now())).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM movie_catalog_ratings WHERE imdbID = \\?").WithArgs(m.ID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO movie_catalog_ratings \\(imdbID, position, source, value\\) VALUES \\(\\?, \\?, \\?, \\?\\), \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs(m.ID, 0, "Internet Movie Database", "9.3/10", m.ID, 1, "Metacritic", "82/100").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+	err := r.Store(context.TODO(), m, domain.PlotShort)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreRollback(t *testing.T) {
+	db, mock := newMock(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO movie_catalog \\(imdbID").WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+	err := r.Store(context.TODO(), &domain.Movies{ID: "tt0111161"}, domain.PlotFull)
+	assert.Equal(t, sql.ErrConnDone, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+const match = "MATCH \\(title, director, actors, plot_short, plot_full\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\)"
+
+func TestFetch(t *testing.T) {
+	db, mock := newMock(t)
+	where := "WHERE " + match + " AND LEFT\\(year, 4\\) <= \\? AND CASE CHAR_LENGTH\\(year\\) WHEN 4 THEN year " +
+		"WHEN 5 THEN '9999' ELSE SUBSTRING\\(year, 6, 4\\) END >= \\? AND type = \\?"
+	args := []driver.Value{"prison escape", "1994", "1994", "movie"}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM movie_catalog " + where).WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery("SELECT imdbID, title, year, type, poster, MATCH \\(title\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\) \\* 2 \\+ " +
+		match + " AS relevance FROM movie_catalog " + where + " ORDER BY relevance DESC, imdbID LIMIT \\? OFFSET \\?").
+		WithArgs(append([]driver.Value{"prison escape", "prison escape"}, append(args, 10, 10)...)...).
+		WillReturnRows(sqlmock.NewRows([]string{"imdbID", "title", "year", "type", "poster", "relevance"}).
+			AddRow("tt0111161", "The Shawshank Redemption", "1994", "movie", "N/A", 3.2).
+			AddRow("tt0000012", "Prison Break Out", "1994", "movie", "N/A", 1.1))
+
+	r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+	page, err := r.Fetch(context.TODO(), domain.SearchCriteria{
+		Searchword: "prison escape",
+		Year:       1994,
+		Type:       domain.MovieTypeMovie,
+		Source:     domain.SearchSourceLocal,
+		Cursor:     repository.EncodeCursor(2),
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Movies, 2)
+	assert.Equal(t, "tt0111161", page.Movies[0].ID)
+	assert.Equal(t, 12, page.Total)
+	assert.Empty(t, page.NextCursor)
+	assert.Equal(t, repository.EncodeCursor(1), page.PrevCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchNotFound(t *testing.T) {
+	db, mock := newMock(t)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM movie_catalog WHERE " + match).WithArgs("Nothing").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	r := movieMysqlRepo.NewMysqlMovieRepository(db, time.Hour)
+	_, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Nothing"})
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
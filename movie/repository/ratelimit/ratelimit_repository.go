@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/ratelimit"
+)
+
+// Options represent the request budgets of the rate limited repository, a zero budget is unlimited
+type Options struct {
+	Provider  string
+	PerSecond float64
+	Burst     int
+	PerDay    int64
+}
+
+type rateLimitedMovieRepository struct {
+	repo   domain.MovieRepository
+	opts   Options
+	bucket *ratelimit.TokenBucket
+	daily  *ratelimit.DailyCounter
+}
+
+// NewRateLimitedMovieRepository will enforce a per second token bucket and a daily budget in front of the
+// given domain.MovieRepository. The daily usage is persisted through quotaRepo so restarts don't reset it
+func NewRateLimitedMovieRepository(repo domain.MovieRepository, quotaRepo domain.QuotaRepository, opts Options) domain.MovieRepository {
+	r := &rateLimitedMovieRepository{
+		repo:  repo,
+		opts:  opts,
+		daily: ratelimit.NewDailyCounter(quotaRepo),
+	}
+	if opts.PerSecond > 0 {
+		r.bucket = ratelimit.NewTokenBucket(opts.PerSecond, opts.Burst)
+	}
+
+	return r
+}
+
+func (r *rateLimitedMovieRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	if err = r.wait(ctx); err != nil {
+		return
+	}
+
+	return r.repo.Fetch(ctx, criteria)
+}
+
+func (r *rateLimitedMovieRepository) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	if err = r.wait(ctx); err != nil {
+		return
+	}
+
+	return r.repo.GetByID(ctx, id, plot)
+}
+
+// wait will take a token from the per second budget and charge one call to the daily budget, or return a
+// *domain.RateLimitError. A refused call is not charged to the daily budget, it never reaches the provider
+func (r *rateLimitedMovieRepository) wait(ctx context.Context) error {
+	if r.bucket != nil {
+		if ok, retryAfter := r.bucket.Take(); !ok {
+			return &domain.RateLimitError{RetryAfter: retryAfter}
+		}
+	}
+
+	if r.opts.PerDay <= 0 {
+		return nil
+	}
+
+	if used, resetIn := r.daily.Used(ctx, r.opts.Provider); used >= r.opts.PerDay {
+		return &domain.RateLimitError{RetryAfter: resetIn}
+	}
+	r.daily.Increment(ctx, r.opts.Provider)
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/repository/ratelimit"
+	"github.com/bxcodec/go-clean-arch/movie/repository/resilience"
+	quotaRepo "github.com/bxcodec/go-clean-arch/quota/repository/mysql"
+)
+
+func TestPerSecondLimit(t *testing.T) {
+	mockMovie := domain.Movies{ID: "tt0111161"}
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Times(3)
+	r := ratelimit.NewRateLimitedMovieRepository(mockMovieRepo, nil, ratelimit.Options{PerSecond: 50, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		_, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+	}
+
+	_, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, domain.ErrRateLimited))
+	var rateLimitErr *domain.RateLimitError
+	require.True(t, errors.As(err, &rateLimitErr))
+	assert.True(t, rateLimitErr.RetryAfter > 0 && rateLimitErr.RetryAfter <= 20*time.Millisecond)
+
+	time.Sleep(25 * time.Millisecond)
+	_, err = r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+	assert.NoError(t, err)
+	mockMovieRepo.AssertExpectations(t)
+}
+
+func TestPerDayLimit(t *testing.T) {
+	mockPage := domain.MoviePage{Total: 1}
+
+	t.Run("persisted", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(mockPage, nil).Once()
+		mockQuotaRepo := new(mocks.QuotaRepository)
+		mockQuotaRepo.On("Used", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(999), nil).Once()
+		mockQuotaRepo.On("Increment", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(1000), nil).Once()
+		mockQuotaRepo.On("Used", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(1000), nil).Once()
+		r := ratelimit.NewRateLimitedMovieRepository(mockMovieRepo, mockQuotaRepo, ratelimit.Options{Provider: "omdb", PerDay: 1000})
+
+		_, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+		assert.NoError(t, err)
+
+		_, err = r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+		var rateLimitErr *domain.RateLimitError
+		require.True(t, errors.As(err, &rateLimitErr))
+		assert.True(t, rateLimitErr.RetryAfter > 0 && rateLimitErr.RetryAfter <= 24*time.Hour)
+
+		mockMovieRepo.AssertExpectations(t)
+		mockQuotaRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-unavailable", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(mockPage, nil).Twice()
+		mockQuotaRepo := new(mocks.QuotaRepository)
+		mockQuotaRepo.On("Used", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("connection refused"))
+		mockQuotaRepo.On("Increment", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("connection refused"))
+		r := ratelimit.NewRateLimitedMovieRepository(mockMovieRepo, mockQuotaRepo, ratelimit.Options{Provider: "omdb", PerDay: 2})
+
+		for i := 0; i < 2; i++ {
+			_, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+			assert.NoError(t, err)
+		}
+
+		_, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+		assert.True(t, errors.Is(err, domain.ErrRateLimited))
+		mockMovieRepo.AssertExpectations(t)
+	})
+}
+
+func TestRejectedNotCharged(t *testing.T) {
+	t.Run("per-day", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		// the provider already used its budget, a refused call only reads api_quota
+		dbMock.ExpectQuery("SELECT used FROM api_quota").WithArgs("omdb", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(2))
+		mockMovieRepo := new(mocks.MovieRepository)
+		r := ratelimit.NewRateLimitedMovieRepository(mockMovieRepo, quotaRepo.NewMysqlQuotaRepository(db), ratelimit.Options{Provider: "omdb", PerDay: 2})
+
+		_, err = r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+		assert.True(t, errors.Is(err, domain.ErrRateLimited))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("per-second", func(t *testing.T) {
+		mockQuotaRepo := new(mocks.QuotaRepository)
+		mockQuotaRepo.On("Used", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+		mockQuotaRepo.On("Increment", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once()
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(domain.MoviePage{}, nil).Once()
+		r := ratelimit.NewRateLimitedMovieRepository(mockMovieRepo, mockQuotaRepo, ratelimit.Options{Provider: "omdb", PerSecond: 0.001, Burst: 1, PerDay: 10})
+
+		_, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+		assert.NoError(t, err)
+
+		// the bucket is empty, the daily budget is neither read nor charged
+		_, err = r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+		assert.True(t, errors.Is(err, domain.ErrRateLimited))
+		mockQuotaRepo.AssertExpectations(t)
+		mockMovieRepo.AssertExpectations(t)
+	})
+}
+
+func TestRetriesChargedOnce(t *testing.T) {
+	mockMovie := domain.Movies{ID: "tt0111161"}
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, errors.New("connection reset")).Twice()
+	mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+	mockQuotaRepo := new(mocks.QuotaRepository)
+	mockQuotaRepo.On("Used", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+	mockQuotaRepo.On("Increment", mock.Anything, "omdb", mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once()
+
+	// the rate limit wraps the retries, the way the providers are assembled
+	retried := resilience.NewResilientMovieRepository(mockMovieRepo, resilience.NewCircuitBreaker(5, time.Minute),
+		resilience.RetryOptions{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	r := ratelimit.NewRateLimitedMovieRepository(retried, mockQuotaRepo, ratelimit.Options{Provider: "omdb", PerSecond: 50, Burst: 1, PerDay: 1})
+
+	res, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+	assert.NoError(t, err)
+	assert.Equal(t, mockMovie, res)
+	mockMovieRepo.AssertExpectations(t)
+	mockQuotaRepo.AssertExpectations(t)
+}
@@ -0,0 +1,111 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// State represent the state of a CircuitBreaker
+type State string
+
+const (
+	// StateClosed let every call through
+	StateClosed State = "closed"
+	// StateOpen reject every call until the open timeout elapsed
+	StateOpen State = "open"
+	// StateHalfOpen let a single trial call through to probe the dependency
+	StateHalfOpen State = "half-open"
+)
+
+// CircuitBreaker trips open after a number of consecutive failures and
+// lets a single trial call through once the open timeout elapsed
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+	state            State
+	failures         int
+	openedAt         time.Time
+	trialInFlight    bool
+	now              func() time.Time
+}
+
+// NewCircuitBreaker will create a closed CircuitBreaker
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            StateClosed,
+		now:              time.Now,
+	}
+}
+
+// State return the current state of the breaker
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be followed by Success, Failure or Release
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.trialInFlight = true
+		return true
+	case StateHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a successful call and closes the breaker
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.trialInFlight = false
+}
+
+// Failure records a failed call and opens the breaker once the threshold is reached
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Release records a call whose outcome says nothing about the dependency health, like a call canceled by the caller
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+}
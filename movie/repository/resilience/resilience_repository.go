@@ -0,0 +1,133 @@
+package resilience
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// RetryOptions represent the retry policy of the resilient repository
+type RetryOptions struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+type resilientMovieRepository struct {
+	repo    domain.MovieRepository
+	breaker *CircuitBreaker
+	retry   RetryOptions
+}
+
+// NewResilientMovieRepository will wrap the given domain.MovieRepository with retries using jittered
+// exponential backoff and the given CircuitBreaker, failing fast with domain.ErrServiceUnavailable while it is open
+func NewResilientMovieRepository(repo domain.MovieRepository, breaker *CircuitBreaker, retry RetryOptions) domain.MovieRepository {
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 1
+	}
+
+	return &resilientMovieRepository{
+		repo:    repo,
+		breaker: breaker,
+		retry:   retry,
+	}
+}
+
+func (r *resilientMovieRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	err = r.do(ctx, func() error {
+		res, err = r.repo.Fetch(ctx, criteria)
+		return err
+	})
+
+	return
+}
+
+func (r *resilientMovieRepository) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	err = r.do(ctx, func() error {
+		res, err = r.repo.GetByID(ctx, id, plot)
+		return err
+	})
+
+	return
+}
+
+func (r *resilientMovieRepository) do(ctx context.Context, call func() error) (err error) {
+	for attempt := 0; attempt < r.retry.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			if err = sleepContext(ctx, r.backoff(attempt)); err != nil {
+				return err
+			}
+		}
+
+		if !r.breaker.Allow() {
+			return domain.ErrServiceUnavailable
+		}
+
+		err = call()
+		switch {
+		case err != nil && ctx.Err() == context.Canceled:
+			r.breaker.Release()
+			return err
+		case err == nil, !isFailure(err):
+			r.breaker.Success()
+			return err
+		}
+
+		r.breaker.Failure()
+		if !retryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// backoff return a random delay between zero and the exponential delay of the given attempt
+func (r *resilientMovieRepository) backoff(attempt int) time.Duration {
+	delay := r.retry.BaseDelay << uint(attempt-1)
+	if delay <= 0 || (r.retry.MaxDelay > 0 && delay > r.retry.MaxDelay) {
+		delay = r.retry.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(delay)))
+}
+
+// isFailure reports whether the error means the provider is unhealthy.
+// Errors answered by the provider itself, like a movie that does not exist, are not worth a retry
+func isFailure(err error) bool {
+	for _, answered := range []error{domain.ErrNotFound, domain.ErrBadParamInput, domain.ErrInvalidAPIKey,
+		domain.ErrRateLimited, domain.ErrTooManyResults} {
+		if errors.Is(err, answered) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// retryable reports whether a failed call is worth another attempt. A call past its deadline has no time
+// left for one, and a payload that can't be decoded would be the same the next time
+func retryable(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return !errors.Is(err, context.DeadlineExceeded) && !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
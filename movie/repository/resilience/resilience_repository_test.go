@@ -0,0 +1,136 @@
+package resilience_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/repository/resilience"
+)
+
+var retryOptions = resilience.RetryOptions{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    5 * time.Millisecond,
+}
+
+func TestGetByIDRetry(t *testing.T) {
+	mockMovie := domain.Movies{ID: "tt0111161", Title: "The Shawshank Redemption"}
+
+	t.Run("success-after-retry", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, errors.New("omdb: unexpected status 503")).Twice()
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+		breaker := resilience.NewCircuitBreaker(5, time.Minute)
+		r := resilience.NewResilientMovieRepository(mockMovieRepo, breaker, retryOptions)
+
+		res, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.NoError(t, err)
+		assert.Equal(t, mockMovie, res)
+		assert.Equal(t, resilience.StateClosed, breaker.State())
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, errors.New("connection reset")).Times(3)
+		r := resilience.NewResilientMovieRepository(mockMovieRepo, resilience.NewCircuitBreaker(5, time.Minute), retryOptions)
+
+		_, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.EqualError(t, err, "connection reset")
+		mockMovieRepo.AssertExpectations(t)
+	})
+
+	t.Run("failure-not-retried", func(t *testing.T) {
+		for _, failure := range []error{
+			context.DeadlineExceeded,
+			fmt.Errorf("omdb: %w", context.DeadlineExceeded),
+			json.Unmarshal([]byte("<html>"), &domain.Movies{}),
+			json.Unmarshal([]byte(`{"imdbID": 42}`), &domain.Movies{}),
+		} {
+			mockMovieRepo := new(mocks.MovieRepository)
+			mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, failure).Once()
+			breaker := resilience.NewCircuitBreaker(1, time.Minute)
+			r := resilience.NewResilientMovieRepository(mockMovieRepo, breaker, retryOptions)
+
+			_, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+			assert.Equal(t, failure, err)
+			// still a failure of the provider
+			assert.Equal(t, resilience.StateOpen, breaker.State(), failure.Error())
+			mockMovieRepo.AssertExpectations(t)
+		}
+	})
+
+	t.Run("not-retried", func(t *testing.T) {
+		mockMovieRepo := new(mocks.MovieRepository)
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(domain.Movies{}, domain.ErrNotFound).Once()
+		r := resilience.NewResilientMovieRepository(mockMovieRepo, resilience.NewCircuitBreaker(5, time.Minute), retryOptions)
+
+		_, err := r.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockMovieRepo.AssertExpectations(t)
+	})
+}
+
+func TestFetchCircuitBreaker(t *testing.T) {
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(domain.MoviePage{}, errors.New("connection refused")).Twice()
+	breaker := resilience.NewCircuitBreaker(2, 20*time.Millisecond)
+	r := resilience.NewResilientMovieRepository(mockMovieRepo, breaker, resilience.RetryOptions{MaxAttempts: 1})
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+		assert.EqualError(t, err, "connection refused")
+	}
+	assert.Equal(t, resilience.StateOpen, breaker.State())
+
+	_, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+	assert.Equal(t, domain.ErrServiceUnavailable, err)
+	mockMovieRepo.AssertExpectations(t)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, resilience.StateHalfOpen, breaker.State())
+
+	mockPage := domain.MoviePage{Movies: []domain.Movies{{ID: "tt0372784"}}, Total: 1}
+	mockMovieRepo.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(mockPage, nil).Once()
+	page, err := r.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+	assert.NoError(t, err)
+	assert.Equal(t, mockPage, page)
+	assert.Equal(t, resilience.StateClosed, breaker.State())
+}
+
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	breaker := resilience.NewCircuitBreaker(1, 10*time.Millisecond)
+	assert.True(t, breaker.Allow())
+	breaker.Failure()
+	assert.False(t, breaker.Allow())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, breaker.Allow())
+	assert.False(t, breaker.Allow(), "only a single trial call is allowed while half-open")
+	breaker.Failure()
+	assert.Equal(t, resilience.StateOpen, breaker.State())
+}
+
+func TestCanceledDoesNotTrip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	mockMovieRepo := new(mocks.MovieRepository)
+	mockMovieRepo.On("GetByID", mock.Anything, "tt0111161", domain.PlotFull).
+		Run(func(args mock.Arguments) { cancel() }).
+		Return(domain.Movies{}, context.Canceled).Once()
+	breaker := resilience.NewCircuitBreaker(1, time.Minute)
+	r := resilience.NewResilientMovieRepository(mockMovieRepo, breaker, retryOptions)
+
+	_, err := r.GetByID(ctx, "tt0111161", domain.PlotFull)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, resilience.StateClosed, breaker.State())
+	mockMovieRepo.AssertExpectations(t)
+}
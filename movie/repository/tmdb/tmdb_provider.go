@@ -0,0 +1,28 @@
+package tmdb
+
+import (
+	"context"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// ProviderName is the name the TMDb provider is known by in the precedence and the provenance of merged movies
+const ProviderName = "tmdb"
+
+type tmdbProvider struct {
+	repo domain.MovieRepository
+}
+
+// NewTMDbProvider will create the domain.MovieProvider of TMDb on top of the given repository, usually the TMDb
+// repository wrapped in its rate limiting and resilience decorators. Unlike OMDb the provider doesn't search
+func NewTMDbProvider(repo domain.MovieRepository) domain.MovieProvider {
+	return &tmdbProvider{repo}
+}
+
+func (p *tmdbProvider) Name() string {
+	return ProviderName
+}
+
+func (p *tmdbProvider) GetByID(ctx context.Context, imdbID string, plot domain.PlotLength) (domain.Movies, error) {
+	return p.repo.GetByID(ctx, imdbID, plot)
+}
@@ -0,0 +1,303 @@
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+const (
+	tmdbBaseURL      = "https://api.themoviedb.org/3"
+	tmdbImageBaseURL = "https://image.tmdb.org/t/p/original"
+	// omdbDateLayout is the date layout of domain.Movies, the TMDb dates are converted to it
+	omdbDateLayout = "02 Jan 2006"
+	// maxActors is the number of leading cast members kept, the same as OMDb
+	maxActors = 4
+)
+
+// errSearchUnsupported will throw on a search, TMDb only looks movies up by imdbID
+var errSearchUnsupported = errors.New("tmdb: search is not supported")
+
+type tmdbRepository struct {
+	Client       *http.Client
+	BaseURL      string
+	ImageBaseURL string
+	APIKey       string
+}
+
+// NewTMDbMovieRepository will create a domain.MovieRepository looking movies up by imdbID on the TMDb API, it has the
+// backdrops and keywords OMDb doesn't have. Searches are not supported. A nil client falls back to http.DefaultClient
+// and empty base URLs to the public TMDb endpoints
+func NewTMDbMovieRepository(client *http.Client, baseURL, imageBaseURL, apiKey string) domain.MovieRepository {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if baseURL == "" {
+		baseURL = tmdbBaseURL
+	}
+	if imageBaseURL == "" {
+		imageBaseURL = tmdbImageBaseURL
+	}
+
+	return &tmdbRepository{
+		Client:       client,
+		BaseURL:      strings.TrimSuffix(baseURL, "/"),
+		ImageBaseURL: strings.TrimSuffix(imageBaseURL, "/"),
+		APIKey:       apiKey,
+	}
+}
+
+func (p *tmdbRepository) Fetch(ctx context.Context, criteria domain.SearchCriteria) (domain.MoviePage, error) {
+	return domain.MoviePage{}, errSearchUnsupported
+}
+
+type named struct {
+	Name string `json:"name"`
+}
+
+type language struct {
+	EnglishName string `json:"english_name"`
+}
+
+type credits struct {
+	Cast []named `json:"cast"`
+	Crew []struct {
+		Name       string `json:"name"`
+		Job        string `json:"job"`
+		Department string `json:"department"`
+	} `json:"crew"`
+}
+
+// details represent the fields shared by the movie and the tv details of TMDb, requested with their keywords and
+// credits appended. Movies list their keywords under keywords and series under results
+type details struct {
+	ID                  int64      `json:"id"`
+	Title               string     `json:"title"`
+	Name                string     `json:"name"`
+	Overview            string     `json:"overview"`
+	ReleaseDate         string     `json:"release_date"`
+	FirstAirDate        string     `json:"first_air_date"`
+	LastAirDate         string     `json:"last_air_date"`
+	Status              string     `json:"status"`
+	Runtime             int        `json:"runtime"`
+	EpisodeRunTime      []int      `json:"episode_run_time"`
+	Genres              []named    `json:"genres"`
+	CreatedBy           []named    `json:"created_by"`
+	SpokenLanguages     []language `json:"spoken_languages"`
+	ProductionCountries []named    `json:"production_countries"`
+	PosterPath          string     `json:"poster_path"`
+	BackdropPath        string     `json:"backdrop_path"`
+	VoteAverage         float64    `json:"vote_average"`
+	VoteCount           int        `json:"vote_count"`
+	Keywords            struct {
+		Keywords []named `json:"keywords"`
+		Results  []named `json:"results"`
+	} `json:"keywords"`
+	Credits credits `json:"credits"`
+}
+
+type findResponse struct {
+	MovieResults []struct {
+		ID int64 `json:"id"`
+	} `json:"movie_results"`
+	TVResults []struct {
+		ID int64 `json:"id"`
+	} `json:"tv_results"`
+}
+
+// GetByID will find the TMDb title of the imdbID and fetch its details, TMDb has a single overview whatever the
+// plot length asked
+func (p *tmdbRepository) GetByID(ctx context.Context, imdbID string, plot domain.PlotLength) (res domain.Movies, err error) {
+	ctx, span := tracing.Start(ctx, "tmdbRepository.GetByID")
+	defer tracing.Finish(span, &err)
+
+	var found findResponse
+	params := url.Values{}
+	params.Set("external_source", "imdb_id")
+	if err = p.get(ctx, "/find/"+url.PathEscape(imdbID), params, &found); err != nil {
+		return
+	}
+
+	var (
+		d         details
+		movieType domain.MovieType
+	)
+	params = url.Values{}
+	params.Set("append_to_response", "keywords,credits")
+	switch {
+	case len(found.MovieResults) > 0:
+		movieType = domain.MovieTypeMovie
+		err = p.get(ctx, "/movie/"+strconv.FormatInt(found.MovieResults[0].ID, 10), params, &d)
+	case len(found.TVResults) > 0:
+		movieType = domain.MovieTypeSeries
+		err = p.get(ctx, "/tv/"+strconv.FormatInt(found.TVResults[0].ID, 10), params, &d)
+	default:
+		return res, domain.ErrNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	return p.movie(imdbID, movieType, d), nil
+}
+
+// movie will convert the TMDb details to the OMDb formats of domain.Movies, so both providers merge and normalize
+// the same way
+func (p *tmdbRepository) movie(imdbID string, movieType domain.MovieType, d details) domain.Movies {
+	m := domain.Movies{
+		ID:       imdbID,
+		Type:     string(movieType),
+		Plot:     d.Overview,
+		Genre:    joinNames(d.Genres),
+		Country:  joinNames(d.ProductionCountries),
+		Poster:   p.image(d.PosterPath),
+		Backdrop: p.image(d.BackdropPath),
+	}
+
+	languages := make([]string, 0, len(d.SpokenLanguages))
+	for _, l := range d.SpokenLanguages {
+		languages = append(languages, l.EnglishName)
+	}
+	m.Language = strings.Join(languages, ", ")
+
+	if len(d.Credits.Cast) > maxActors {
+		d.Credits.Cast = d.Credits.Cast[:maxActors]
+	}
+	m.Actors = joinNames(d.Credits.Cast)
+
+	var directors, writers []string
+	for _, c := range d.Credits.Crew {
+		switch {
+		case c.Job == "Director":
+			directors = appendUnique(directors, c.Name)
+		case c.Department == "Writing":
+			writers = appendUnique(writers, c.Name)
+		}
+	}
+	m.Director = strings.Join(directors, ", ")
+	m.Writer = strings.Join(writers, ", ")
+
+	if d.VoteCount > 0 {
+		m.Ratings = []domain.Rating{{Source: "TMDb", Value: strconv.FormatFloat(d.VoteAverage, 'f', 1, 64) + "/10"}}
+	}
+
+	if movieType == domain.MovieTypeSeries {
+		m.Title = d.Name
+		m.Director = joinNames(d.CreatedBy)
+		m.Keywords = joinNames(d.Keywords.Results)
+		m.Released = formatDate(d.FirstAirDate)
+		m.Year = year(d.FirstAirDate)
+		if m.Year != "" {
+			// a series still running has an open range of years, as in OMDb
+			m.Year += "–"
+			if d.Status == "Ended" || d.Status == "Canceled" {
+				m.Year += year(d.LastAirDate)
+			}
+		}
+		if len(d.EpisodeRunTime) > 0 {
+			m.Runtime = strconv.Itoa(d.EpisodeRunTime[0]) + " min"
+		}
+		return m
+	}
+
+	m.Title = d.Title
+	m.Keywords = joinNames(d.Keywords.Keywords)
+	m.Released = formatDate(d.ReleaseDate)
+	m.Year = year(d.ReleaseDate)
+	if d.Runtime > 0 {
+		m.Runtime = strconv.Itoa(d.Runtime) + " min"
+	}
+	return m
+}
+
+func (p *tmdbRepository) image(path string) string {
+	if path == "" {
+		return ""
+	}
+	return p.ImageBaseURL + path
+}
+
+// get will call the TMDb endpoint at path and decode the payload into dest, the call is traced as a client span
+// propagated to TMDb through the traceparent header
+func (p *tmdbRepository) get(ctx context.Context, path string, params url.Values, dest interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "tmdb GET", trace.WithSpanKind(trace.SpanKindClient))
+	defer tracing.Finish(span, &err)
+	// the api key is left out of the traced url
+	span.SetAttributes(
+		attribute.String("http.method", http.MethodGet),
+		attribute.String("http.url", p.BaseURL+path+"?"+params.Encode()),
+	)
+
+	params.Set("api_key", p.APIKey)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set("Accept", "application/json")
+	tracing.Inject(ctx, request.Header)
+
+	response, err := p.Client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	span.SetAttributes(attribute.Int("http.status_code", response.StatusCode))
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return domain.ErrNotFound
+	case response.StatusCode == http.StatusUnauthorized:
+		return domain.ErrInvalidAPIKey
+	case response.StatusCode == http.StatusTooManyRequests:
+		return domain.ErrRateLimited
+	case response.StatusCode != http.StatusOK:
+		return fmt.Errorf("tmdb: unexpected status %d", response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(dest)
+}
+
+func joinNames(list []named) string {
+	names := make([]string, 0, len(list))
+	for _, n := range list {
+		names = append(names, n.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// formatDate converts the 2006-01-02 dates of TMDb to the layout of OMDb
+func formatDate(s string) string {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return ""
+	}
+	return t.Format(omdbDateLayout)
+}
+
+func year(date string) string {
+	if len(date) < 4 {
+		return ""
+	}
+	return date[:4]
+}
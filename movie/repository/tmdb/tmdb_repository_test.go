@@ -0,0 +1,158 @@
+package tmdb_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	movieRepo "github.com/bxcodec/go-clean-arch/movie/repository/movie"
+	"github.com/bxcodec/go-clean-arch/movie/repository/tmdb"
+)
+
+// newTMDbServer will fake the TMDb endpoints with the given payloads by path, any other path is answered like TMDb
+// answers an unknown resource
+func newTMDbServer(t *testing.T, payloads map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("api_key"))
+		w.Header().Set("Content-Type", "application/json")
+
+		body, ok := payloads[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"success":false,"status_code":34,"status_message":"The resource you requested could not be found."}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestGetByIDMovie(t *testing.T) {
+	srv := newTMDbServer(t, map[string]string{
+		"/find/tt0111161": `{"movie_results":[{"id":278,"title":"The Shawshank Redemption"}],"tv_results":[]}`,
+		"/movie/278": `{
+			"id": 278, "imdb_id": "tt0111161", "title": "The Shawshank Redemption",
+			"overview": "Framed in the 1940s for the double murder of his wife and her lover...",
+			"release_date": "1994-09-23", "runtime": 142,
+			"genres": [{"id": 18, "name": "Drama"}, {"id": 80, "name": "Crime"}],
+			"spoken_languages": [{"english_name": "English", "iso_639_1": "en"}],
+			"production_countries": [{"iso_3166_1": "US", "name": "United States of America"}],
+			"poster_path": "/poster.jpg", "backdrop_path": "/backdrop.jpg",
+			"vote_average": 8.7, "vote_count": 26000,
+			"keywords": {"keywords": [{"id": 378, "name": "prison"}, {"id": 417, "name": "corruption"}]},
+			"credits": {
+				"cast": [{"name": "Tim Robbins"}, {"name": "Morgan Freeman"}, {"name": "Bob Gunton"},
+					{"name": "William Sadler"}, {"name": "Clancy Brown"}],
+				"crew": [{"name": "Frank Darabont", "job": "Director", "department": "Directing"},
+					{"name": "Frank Darabont", "job": "Screenplay", "department": "Writing"},
+					{"name": "Stephen King", "job": "Novel", "department": "Writing"},
+					{"name": "Roger Deakins", "job": "Director of Photography", "department": "Camera"}]
+			}
+		}`,
+	})
+	defer srv.Close()
+
+	p := tmdb.NewTMDbProvider(tmdb.NewTMDbMovieRepository(srv.Client(), srv.URL, "https://images.example.com/t/p/original/", "secret"))
+	assert.Equal(t, tmdb.ProviderName, p.Name())
+
+	m, err := p.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Movies{
+		ID:       "tt0111161",
+		Title:    "The Shawshank Redemption",
+		Year:     "1994",
+		Released: "23 Sep 1994",
+		Runtime:  "142 min",
+		Genre:    "Drama, Crime",
+		Director: "Frank Darabont",
+		Writer:   "Frank Darabont, Stephen King",
+		Actors:   "Tim Robbins, Morgan Freeman, Bob Gunton, William Sadler",
+		Plot:     "Framed in the 1940s for the double murder of his wife and her lover...",
+		Language: "English",
+		Country:  "United States of America",
+		Poster:   "https://images.example.com/t/p/original/poster.jpg",
+		Backdrop: "https://images.example.com/t/p/original/backdrop.jpg",
+		Keywords: "prison, corruption",
+		Type:     "movie",
+		Ratings:  []domain.Rating{{Source: "TMDb", Value: "8.7/10"}},
+	}, m)
+
+	// the normalized movie reads the converted fields like the OMDb ones
+	normalized := movieRepo.Normalize(m)
+	assert.Equal(t, 1994, normalized.Year)
+	assert.Equal(t, []string{"prison", "corruption"}, normalized.Keywords)
+	assert.InDelta(t, 87, normalized.Ratings[0].Score, 0.001)
+}
+
+func TestGetByIDSeries(t *testing.T) {
+	srv := newTMDbServer(t, map[string]string{
+		"/find/tt0386676": `{"movie_results":[],"tv_results":[{"id":2316}]}`,
+		"/tv/2316": `{
+			"id": 2316, "name": "The Office", "first_air_date": "2005-03-24", "last_air_date": "2013-05-16",
+			"status": "Ended", "episode_run_time": [22], "created_by": [{"name": "Greg Daniels"}],
+			"keywords": {"results": [{"name": "workplace"}, {"name": "mockumentary"}]},
+			"vote_count": 0
+		}`,
+	})
+	defer srv.Close()
+
+	p := tmdb.NewTMDbProvider(tmdb.NewTMDbMovieRepository(srv.Client(), srv.URL, "", "secret"))
+	m, err := p.GetByID(context.TODO(), "tt0386676", domain.PlotShort)
+	require.NoError(t, err)
+	assert.Equal(t, "The Office", m.Title)
+	assert.Equal(t, "series", m.Type)
+	assert.Equal(t, "2005–2013", m.Year)
+	assert.Equal(t, "22 min", m.Runtime)
+	assert.Equal(t, "Greg Daniels", m.Director)
+	assert.Equal(t, "workplace, mockumentary", m.Keywords)
+	assert.Empty(t, m.Ratings)
+}
+
+func TestGetByIDError(t *testing.T) {
+	t.Run("not-found", func(t *testing.T) {
+		srv := newTMDbServer(t, map[string]string{
+			"/find/tt0000000": `{"movie_results":[],"tv_results":[]}`,
+		})
+		defer srv.Close()
+
+		p := tmdb.NewTMDbProvider(tmdb.NewTMDbMovieRepository(srv.Client(), srv.URL, "", "secret"))
+		_, err := p.GetByID(context.TODO(), "tt0000000", domain.PlotFull)
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	tests := []struct {
+		name     string
+		status   int
+		expected error
+	}{
+		{"invalid-key", http.StatusUnauthorized, domain.ErrInvalidAPIKey},
+		{"limit", http.StatusTooManyRequests, domain.ErrRateLimited},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			p := tmdb.NewTMDbProvider(tmdb.NewTMDbMovieRepository(srv.Client(), srv.URL, "", "secret"))
+			_, err := p.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+
+	t.Run("server-error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		p := tmdb.NewTMDbProvider(tmdb.NewTMDbMovieRepository(srv.Client(), srv.URL, "", "secret"))
+		_, err := p.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+		assert.EqualError(t, err, "tmdb: unexpected status 503")
+	})
+}
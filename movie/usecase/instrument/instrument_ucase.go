@@ -0,0 +1,52 @@
+package instrument
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/metrics"
+)
+
+type instrumentedMovieUsecase struct {
+	usecase  domain.MovieUsecase
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewInstrumentedMovieUsecase will count and time every call made to the given domain.MovieUsecase
+func NewInstrumentedMovieUsecase(u domain.MovieUsecase, reg prometheus.Registerer) (domain.MovieUsecase, error) {
+	calls, err := metrics.NewCounterVec(reg, "movie_usecase_calls_total",
+		"Calls made to the movie usecase by method and outcome.", "method", "outcome")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := metrics.NewHistogramVec(reg, "movie_usecase_duration_seconds",
+		"Latency of the calls made to the movie usecase.", "method")
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedMovieUsecase{
+		usecase:  u,
+		calls:    calls,
+		duration: duration,
+	}, nil
+}
+
+func (i *instrumentedMovieUsecase) Fetch(ctx context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	defer i.observe("Fetch", time.Now(), &err)
+	return i.usecase.Fetch(ctx, criteria)
+}
+
+func (i *instrumentedMovieUsecase) GetByID(ctx context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	defer i.observe("GetByID", time.Now(), &err)
+	return i.usecase.GetByID(ctx, id, plot)
+}
+
+func (i *instrumentedMovieUsecase) observe(method string, start time.Time, err *error) {
+	i.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	i.calls.WithLabelValues(method, metrics.Outcome(*err)).Inc()
+}
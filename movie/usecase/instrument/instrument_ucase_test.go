@@ -0,0 +1,42 @@
+package instrument_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/movie/usecase/instrument"
+)
+
+func TestInstrumentedMovieUsecase(t *testing.T) {
+	mockUCase := new(mocks.MovieUsecase)
+	mockPage := domain.MoviePage{Movies: []domain.Movies{{ID: "tt0372784"}}, Total: 1}
+	mockUCase.On("Fetch", mock.Anything, domain.SearchCriteria{Searchword: "Batman"}).Return(mockPage, nil).Once()
+	mockUCase.On("GetByID", mock.Anything, "tt0111161", domain.PlotFull).Return(domain.Movies{}, domain.ErrServiceUnavailable).Once()
+
+	reg := prometheus.NewRegistry()
+	u, err := instrument.NewInstrumentedMovieUsecase(mockUCase, reg)
+	require.NoError(t, err)
+
+	page, err := u.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman"})
+	assert.NoError(t, err)
+	assert.Equal(t, mockPage, page)
+	_, err = u.GetByID(context.TODO(), "tt0111161", domain.PlotFull)
+	assert.Equal(t, domain.ErrServiceUnavailable, err)
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	buf := rec.Body
+	assert.Contains(t, buf.String(), `movie_usecase_calls_total{method="Fetch",outcome="ok"} 1`)
+	assert.Contains(t, buf.String(), `movie_usecase_calls_total{method="GetByID",outcome="unavailable"} 1`)
+	mockUCase.AssertExpectations(t)
+}
@@ -16,94 +16,98 @@ import (
 
 func TestFetchMovies(t *testing.T) {
 	mockMovieRepo := new(mocks.MovieRepository)
-	mockMovie := domain.Movie{
-		Title:   "Hello",
-		Content: "Content",
+	mockMovie := domain.Movies{
+		ID:    "tt0372784",
+		Title: "Batman Begins",
 	}
 
-	mockListArtilce := make([]domain.Movie, 0)
-	mockListArtilce = append(mockListArtilce, mockMovie)
+	mockListMovie := make([]domain.Movies, 0)
+	mockListMovie = append(mockListMovie, mockMovie)
+	mockPage := domain.MoviePage{
+		Movies:     mockListMovie,
+		Total:      42,
+		NextCursor: "next-cursor",
+	}
 
 	t.Run("success", func(t *testing.T) {
-		mockMovieRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
-		mockLogmovie := domain.Logmovie{
-			ID:   1,
-			Name: "Iman Tumorang",
-		}
-		mockLogmovierepo := new(mocks.LogmovieRepository)
-		mockLogmovierepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockLogmovie, nil)
-		u := ucase.NewMovieUsecase(mockMovieRepo, mockLogmovierepo, time.Second*2)
-		num := int64(1)
+		mockMovieRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.SearchCriteria")).Return(mockPage, nil).Once()
+		u := ucase.NewMovieUsecase(mockMovieRepo, time.Second*2)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		page, err := u.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Cursor: cursor})
 		cursorExpected := "next-cursor"
-		assert.Equal(t, cursorExpected, nextCursor)
-		assert.NotEmpty(t, nextCursor)
+		assert.Equal(t, cursorExpected, page.NextCursor)
+		assert.NotEmpty(t, page.NextCursor)
 		assert.NoError(t, err)
-		assert.Len(t, list, len(mockListArtilce))
+		assert.Len(t, page.Movies, len(mockListMovie))
 
 		mockMovieRepo.AssertExpectations(t)
-		mockLogmovierepo.AssertExpectations(t)
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockMovieRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
+		mockMovieRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.SearchCriteria")).Return(domain.MoviePage{}, errors.New("Unexpexted Error")).Once()
 
-		mockLogmovierepo := new(mocks.LogmovieRepository)
-		u := ucase.NewMovieUsecase(mockMovieRepo, mockLogmovierepo, time.Second*2)
-		num := int64(1)
+		u := ucase.NewMovieUsecase(mockMovieRepo, time.Second*2)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		page, err := u.Fetch(context.TODO(), domain.SearchCriteria{Searchword: "Batman", Cursor: cursor})
 
-		assert.Empty(t, nextCursor)
+		assert.Empty(t, page.NextCursor)
 		assert.Error(t, err)
-		assert.Len(t, list, 0)
+		assert.Len(t, page.Movies, 0)
 		mockMovieRepo.AssertExpectations(t)
-		mockLogmovierepo.AssertExpectations(t)
 	})
 
+	t.Run("filters", func(t *testing.T) {
+		criteria := domain.SearchCriteria{Searchword: "Batman", Year: 2005, Type: domain.MovieTypeMovie}
+		mockMovieRepo.On("Fetch", mock.Anything, criteria).Return(mockPage, nil).Once()
+		u := ucase.NewMovieUsecase(mockMovieRepo, time.Second*2)
+
+		_, err := u.Fetch(context.TODO(), criteria)
+		assert.NoError(t, err)
+		mockMovieRepo.AssertExpectations(t)
+	})
 }
 
 func TestGetMovieByID(t *testing.T) {
 	mockMovieRepo := new(mocks.MovieRepository)
-	mockMovie := domain.Movie{
-		Title:   "Hello",
-		Content: "Content",
-	}
-	mockLogmovie := domain.Logmovie{
-		ID:   1,
-		Name: "Iman Tumorang",
+	mockMovie := domain.Movies{
+		ID:    "tt0372784",
+		Title: "Batman Begins",
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockMovieRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockMovie, nil).Once()
-		mockLogmovierepo := new(mocks.LogmovieRepository)
-		mockLogmovierepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockLogmovie, nil)
-		u := ucase.NewMovieUsecase(mockMovieRepo, mockLogmovierepo, time.Second*2)
+		mockMovieRepo.On("GetByID", mock.Anything, mock.AnythingOfType("string"), domain.PlotFull).Return(mockMovie, nil).Once()
+		u := ucase.NewMovieUsecase(mockMovieRepo, time.Second*2)
 
-		a, err := u.GetByID(context.TODO(), mockMovie.ID)
+		a, err := u.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, a)
 
 		mockMovieRepo.AssertExpectations(t)
-		mockLogmovierepo.AssertExpectations(t)
 	})
 	t.Run("error-failed", func(t *testing.T) {
-		mockMovieRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Movie{}, errors.New("Unexpected")).Once()
+		mockMovieRepo.On("GetByID", mock.Anything, mock.AnythingOfType("string"), domain.PlotFull).Return(domain.Movies{}, errors.New("Unexpected")).Once()
 
-		mockLogmovierepo := new(mocks.LogmovieRepository)
-		u := ucase.NewMovieUsecase(mockMovieRepo, mockLogmovierepo, time.Second*2)
+		u := ucase.NewMovieUsecase(mockMovieRepo, time.Second*2)
 
-		a, err := u.GetByID(context.TODO(), mockMovie.ID)
+		a, err := u.GetByID(context.TODO(), mockMovie.ID, domain.PlotFull)
 
 		assert.Error(t, err)
-		assert.Equal(t, domain.Movie{}, a)
+		assert.Equal(t, domain.Movies{}, a)
 
 		mockMovieRepo.AssertExpectations(t)
-		mockLogmovierepo.AssertExpectations(t)
 	})
 
+	t.Run("plot", func(t *testing.T) {
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotShort).Return(mockMovie, nil).Once()
+		mockMovieRepo.On("GetByID", mock.Anything, mockMovie.ID, domain.PlotFull).Return(mockMovie, nil).Once()
+		u := ucase.NewMovieUsecase(mockMovieRepo, time.Second*2)
+
+		_, err := u.GetByID(context.TODO(), mockMovie.ID, domain.PlotShort)
+		assert.NoError(t, err)
+		// no plot asks for the full one
+		_, err = u.GetByID(context.TODO(), mockMovie.ID, "")
+		assert.NoError(t, err)
+		mockMovieRepo.AssertExpectations(t)
+	})
 }
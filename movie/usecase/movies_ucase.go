@@ -4,7 +4,10 @@ import (
 	"context"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/tracing"
 )
 
 type movieUsecase struct {
@@ -20,23 +23,41 @@ func NewMovieUsecase(a domain.MovieRepository, timeout time.Duration) domain.Mov
 	}
 }
 
-func (a *movieUsecase) Fetch(c context.Context, cursor string, searchword string) (res []domain.Movies, nextCursor string, err error) {
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+func (a *movieUsecase) Fetch(c context.Context, criteria domain.SearchCriteria) (res domain.MoviePage, err error) {
+	ctx, span := tracing.Start(c, "movieUsecase.Fetch")
+	defer tracing.Finish(span, &err)
+	span.SetAttributes(attribute.String("movie.search", criteria.Searchword))
+	if criteria.Year != 0 {
+		span.SetAttributes(attribute.Int("movie.year", criteria.Year))
+	}
+	if criteria.Type != "" {
+		span.SetAttributes(attribute.String("movie.type", string(criteria.Type)))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
 	defer cancel()
 
-	res, nextCursor, err = a.movieRepo.Fetch(ctx, cursor, searchword)
+	res, err = a.movieRepo.Fetch(ctx, criteria)
 	if err != nil {
-		return nil, "", err
+		return domain.MoviePage{}, err
 	}
 
-	return res, nextCursor, nil
+	return res, nil
 }
 
-func (a *movieUsecase) GetByID(c context.Context, id string) (res domain.Movies, err error) {
-	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+// GetByID will get the movie with its full plot unless plot asks for the short one
+func (a *movieUsecase) GetByID(c context.Context, id string, plot domain.PlotLength) (res domain.Movies, err error) {
+	ctx, span := tracing.Start(c, "movieUsecase.GetByID")
+	defer tracing.Finish(span, &err)
+	span.SetAttributes(attribute.String("movie.imdb_id", id))
+	if plot != domain.PlotShort {
+		plot = domain.PlotFull
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
 	defer cancel()
 
-	res, err = a.movieRepo.GetByID(ctx, id)
+	res, err = a.movieRepo.GetByID(ctx, id, plot)
 	if err != nil {
 		return
 	}
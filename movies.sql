@@ -31,6 +31,112 @@ CREATE TABLE `movies` (
   `year` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
   `released` varchar(45),
   `imdbRating` varchar(45),
-  PRIMARY KEY (`id`)
+  `view_count` int(11) NOT NULL DEFAULT 1,
+  `last_viewed_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_imdbID` (`imdbID`),
+  KEY `idx_view_count` (`view_count`)
 ) ENGINE=InnoDB AUTO_INCREMENT=7 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `movie_catalog`
+--
+
+DROP TABLE IF EXISTS `movie_catalog`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `movie_catalog` (
+  `imdbID` varchar(16) COLLATE utf8_unicode_ci NOT NULL,
+  `title` varchar(255) COLLATE utf8_unicode_ci NOT NULL,
+  `year` varchar(16) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `rated` varchar(16) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `released` varchar(32) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `runtime` varchar(32) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `genre` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `director` text COLLATE utf8_unicode_ci NOT NULL,
+  `writer` text COLLATE utf8_unicode_ci NOT NULL,
+  `actors` text COLLATE utf8_unicode_ci NOT NULL,
+  `plot_short` text COLLATE utf8_unicode_ci,
+  `plot_full` text COLLATE utf8_unicode_ci,
+  `language` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `country` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `awards` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `poster` varchar(512) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `backdrop` varchar(512) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `keywords` text COLLATE utf8_unicode_ci NOT NULL,
+  `type` varchar(16) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `metascore` varchar(16) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `imdbRating` varchar(16) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `imdbVotes` varchar(32) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `dvd` varchar(32) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `sources` text COLLATE utf8_unicode_ci,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`imdbID`),
+  FULLTEXT KEY `ft_title` (`title`),
+  FULLTEXT KEY `ft_search` (`title`,`director`,`actors`,`plot_short`,`plot_full`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `movie_catalog_ratings`
+--
+
+DROP TABLE IF EXISTS `movie_catalog_ratings`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `movie_catalog_ratings` (
+  `imdbID` varchar(16) COLLATE utf8_unicode_ci NOT NULL,
+  `position` int(11) NOT NULL,
+  `source` varchar(64) COLLATE utf8_unicode_ci NOT NULL,
+  `value` varchar(32) COLLATE utf8_unicode_ci NOT NULL,
+  PRIMARY KEY (`imdbID`,`position`),
+  CONSTRAINT `fk_ratings_movie` FOREIGN KEY (`imdbID`) REFERENCES `movie_catalog` (`imdbID`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `api_quota`
+--
+
+DROP TABLE IF EXISTS `api_quota`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `api_quota` (
+  `provider` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `day` date NOT NULL,
+  `used` int(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`provider`,`day`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `api_clients`
+--
+
+DROP TABLE IF EXISTS `api_clients`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `api_clients` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `key_hash` char(64) COLLATE utf8_unicode_ci NOT NULL,
+  `per_second` double NOT NULL DEFAULT 0,
+  `burst` int(11) NOT NULL DEFAULT 0,
+  `daily_quota` int(11) NOT NULL DEFAULT 0,
+  `active` tinyint(1) NOT NULL DEFAULT 1,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_key_hash` (`key_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Client of the local setup, its API key is `local-dev-key`
+--
+
+LOCK TABLES `api_clients` WRITE;
+/*!40000 ALTER TABLE `api_clients` DISABLE KEYS */;
+INSERT INTO `api_clients` (`name`, `key_hash`) VALUES ('local-dev',SHA2('local-dev-key',256));
+/*!40000 ALTER TABLE `api_clients` ENABLE KEYS */;
+UNLOCK TABLES;
@@ -0,0 +1,137 @@
+package omdbfake
+
+import (
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// DefaultMovies returns the fixtures served when none are given: a few movies with their full details, enough
+// Batman titles for a search to span two pages, two series and an episode
+func DefaultMovies() []Movie {
+	return []Movie{
+		{
+			Title:      "The Shawshank Redemption",
+			Year:       "1994",
+			Rated:      "R",
+			Released:   "14 Oct 1994",
+			Runtime:    "142 min",
+			Genre:      "Drama",
+			Director:   "Frank Darabont",
+			Writer:     "Stephen King, Frank Darabont",
+			Actors:     "Tim Robbins, Morgan Freeman, Bob Gunton",
+			Plot:       "Over the course of several years, two convicts form a friendship, seeking consolation and, eventually, redemption through basic compassion.",
+			ShortPlot:  "Two imprisoned men bond over a number of years.",
+			Language:   "English",
+			Country:    "United States",
+			Awards:     "Nominated for 7 Oscars. 21 wins & 43 nominations total",
+			Poster:     "N/A",
+			Ratings:    []domain.Rating{{Source: "Internet Movie Database", Value: "9.3/10"}, {Source: "Rotten Tomatoes", Value: "91%"}, {Source: "Metacritic", Value: "82/100"}},
+			Metascore:  "82",
+			ImdbRating: "9.3",
+			ImdbVotes:  "2,713,581",
+			ImdbID:     "tt0111161",
+			Type:       "movie",
+			DVD:        "21 Dec 1999",
+		},
+		{
+			Title:      "The Godfather",
+			Year:       "1972",
+			Rated:      "R",
+			Released:   "24 Mar 1972",
+			Runtime:    "175 min",
+			Genre:      "Crime, Drama",
+			Director:   "Francis Ford Coppola",
+			Writer:     "Mario Puzo, Francis Ford Coppola",
+			Actors:     "Marlon Brando, Al Pacino, James Caan",
+			Plot:       "The aging patriarch of an organized crime dynasty transfers control of his clandestine empire to his reluctant son.",
+			Language:   "English, Italian, Latin",
+			Country:    "United States",
+			Awards:     "Won 3 Oscars. 31 wins & 31 nominations total",
+			Poster:     "N/A",
+			Ratings:    []domain.Rating{{Source: "Internet Movie Database", Value: "9.2/10"}, {Source: "Rotten Tomatoes", Value: "97%"}, {Source: "Metacritic", Value: "100/100"}},
+			Metascore:  "100",
+			ImdbRating: "9.2",
+			ImdbVotes:  "1,890,000",
+			ImdbID:     "tt0068646",
+			Type:       "movie",
+			DVD:        "N/A",
+		},
+		{
+			Title:      "Batman Begins",
+			Year:       "2005",
+			Rated:      "PG-13",
+			Released:   "15 Jun 2005",
+			Runtime:    "140 min",
+			Genre:      "Action, Crime, Drama",
+			Director:   "Christopher Nolan",
+			Writer:     "Bob Kane, David S. Goyer, Christopher Nolan",
+			Actors:     "Christian Bale, Michael Caine, Ken Watanabe",
+			Plot:       "After witnessing his parents' death, Bruce learns the art of fighting to confront injustice. When he returns to Gotham as Batman, he must stop a secret society that intends to destroy the city.",
+			ShortPlot:  "After training with his mentor, Batman begins his fight to free crime-ridden Gotham City from corruption.",
+			Language:   "English, Mandarin",
+			Country:    "United States, United Kingdom",
+			Awards:     "Nominated for 1 Oscar. 14 wins & 79 nominations total",
+			Poster:     "N/A",
+			Ratings:    []domain.Rating{{Source: "Internet Movie Database", Value: "8.2/10"}, {Source: "Rotten Tomatoes", Value: "85%"}},
+			Metascore:  "70",
+			ImdbRating: "8.2",
+			ImdbVotes:  "1,500,000",
+			ImdbID:     "tt0372784",
+			Type:       "movie",
+			DVD:        "18 Oct 2005",
+		},
+		{
+			Title:      "The Dark Knight",
+			Year:       "2008",
+			Rated:      "PG-13",
+			Released:   "18 Jul 2008",
+			Runtime:    "152 min",
+			Genre:      "Action, Crime, Drama",
+			Director:   "Christopher Nolan",
+			Writer:     "Jonathan Nolan, Christopher Nolan, David S. Goyer",
+			Actors:     "Christian Bale, Heath Ledger, Aaron Eckhart",
+			Plot:       "When the menace known as the Joker wreaks havoc and chaos on the people of Gotham, Batman must accept one of the greatest psychological and physical tests of his ability to fight injustice.",
+			Language:   "English, Mandarin",
+			Country:    "United States, United Kingdom",
+			Awards:     "Won 2 Oscars. 164 wins & 164 nominations total",
+			Poster:     "N/A",
+			Ratings:    []domain.Rating{{Source: "Internet Movie Database", Value: "9.0/10"}, {Source: "Rotten Tomatoes", Value: "94%"}},
+			Metascore:  "84",
+			ImdbRating: "9.0",
+			ImdbVotes:  "2,690,000",
+			ImdbID:     "tt0468569",
+			Type:       "movie",
+			DVD:        "09 Dec 2008",
+		},
+		{Title: "The Dark Knight Rises", Year: "2012", ImdbID: "tt1345836", Type: "movie", Poster: "N/A"},
+		{Title: "Batman", Year: "1989", ImdbID: "tt0096895", Type: "movie", Poster: "N/A"},
+		{Title: "Batman Returns", Year: "1992", ImdbID: "tt0103776", Type: "movie", Poster: "N/A"},
+		{Title: "Batman Forever", Year: "1995", ImdbID: "tt0112462", Type: "movie", Poster: "N/A"},
+		{Title: "Batman & Robin", Year: "1997", ImdbID: "tt0118688", Type: "movie", Poster: "N/A"},
+		{Title: "Batman: Mask of the Phantasm", Year: "1993", ImdbID: "tt0106364", Type: "movie", Poster: "N/A"},
+		{Title: "Batman v Superman: Dawn of Justice", Year: "2016", ImdbID: "tt2975590", Type: "movie", Poster: "N/A"},
+		{Title: "The Lego Batman Movie", Year: "2017", ImdbID: "tt4116284", Type: "movie", Poster: "N/A"},
+		{Title: "The Batman", Year: "2022", ImdbID: "tt1877830", Type: "movie", Poster: "N/A"},
+		{Title: "Batman: Under the Red Hood", Year: "2010", ImdbID: "tt1569923", Type: "movie", Poster: "N/A"},
+		{Title: "Batman: The Dark Knight Returns, Part 1", Year: "2012", ImdbID: "tt2313197", Type: "movie", Poster: "N/A"},
+		{Title: "Batman: The Animated Series", Year: "1992–1995", ImdbID: "tt0103359", Type: "series", Poster: "N/A"},
+		{
+			Title:      "The Office",
+			Year:       "2005–2013",
+			Rated:      "TV-14",
+			Released:   "24 Mar 2005",
+			Runtime:    "22 min",
+			Genre:      "Comedy",
+			Writer:     "Greg Daniels, Ricky Gervais, Stephen Merchant",
+			Actors:     "Steve Carell, Jenna Fischer, John Krasinski",
+			Plot:       "A mockumentary on a group of typical office workers, where the workday consists of ego clashes, inappropriate behavior, tedium and romance.",
+			Language:   "English",
+			Country:    "United States",
+			Poster:     "N/A",
+			ImdbRating: "9.0",
+			ImdbVotes:  "700,000",
+			ImdbID:     "tt0386676",
+			Type:       "series",
+		},
+		{Title: "Ozymandias", Year: "2013", Released: "15 Sep 2013", Runtime: "47 min", ImdbID: "tt2301451", Type: "episode", Poster: "N/A"},
+	}
+}
@@ -0,0 +1,308 @@
+package omdbfake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// pageSize is the number of movies per page of a search, the same as OMDb
+const pageSize = 10
+
+// maxPage is the last page OMDb serves
+const maxPage = 100
+
+var imdbIDPattern = regexp.MustCompile(`^tt\d{7,}$`)
+
+// Movie represent a fixture, in the JSON shape OMDb serves it. ShortPlot is served for plot=short, falling back
+// to Plot when empty
+type Movie struct {
+	Title      string          `json:"Title"`
+	Year       string          `json:"Year"`
+	Rated      string          `json:"Rated,omitempty"`
+	Released   string          `json:"Released,omitempty"`
+	Runtime    string          `json:"Runtime,omitempty"`
+	Genre      string          `json:"Genre,omitempty"`
+	Director   string          `json:"Director,omitempty"`
+	Writer     string          `json:"Writer,omitempty"`
+	Actors     string          `json:"Actors,omitempty"`
+	Plot       string          `json:"Plot,omitempty"`
+	ShortPlot  string          `json:"ShortPlot,omitempty"`
+	Language   string          `json:"Language,omitempty"`
+	Country    string          `json:"Country,omitempty"`
+	Awards     string          `json:"Awards,omitempty"`
+	Poster     string          `json:"Poster"`
+	Ratings    []domain.Rating `json:"Ratings,omitempty"`
+	Metascore  string          `json:"Metascore,omitempty"`
+	ImdbRating string          `json:"imdbRating,omitempty"`
+	ImdbVotes  string          `json:"imdbVotes,omitempty"`
+	ImdbID     string          `json:"imdbID"`
+	Type       string          `json:"Type"`
+	DVD        string          `json:"DVD,omitempty"`
+}
+
+// Options represent the fixtures served and the failures simulated by the Server
+type Options struct {
+	// Movies are the fixtures served, DefaultMovies when nil
+	Movies []Movie
+	// APIKey is the only key accepted when set, any key is accepted otherwise
+	APIKey string
+	// Latency delays every answer, unless the request is canceled first
+	Latency time.Duration
+	// RequestLimit answers "Request limit reached!" once that many requests were served, zero means no limit
+	RequestLimit int
+	// FailEvery answers 503 to every FailEvery-th request, zero means never
+	FailEvery int
+}
+
+// Server is a deterministic fake of the OMDb API serving fixtures for the s, i, t, page, type, y and plot query
+// params, answering with the statuses and error messages OMDb uses
+type Server struct {
+	movies []Movie
+	opts   Options
+
+	mu       sync.Mutex
+	requests []url.Values
+	failures []int
+}
+
+// New will create a Server, a http.Handler to be mounted or served as is
+func New(opts Options) *Server {
+	movies := opts.Movies
+	if movies == nil {
+		movies = DefaultMovies()
+	}
+
+	return &Server{
+		movies: movies,
+		opts:   opts,
+	}
+}
+
+// NewTestServer will start a Server on a local port, to be closed by the test
+func NewTestServer(opts Options) (*httptest.Server, *Server) {
+	s := New(opts)
+	return httptest.NewServer(s), s
+}
+
+// LoadMovies will read fixtures from a JSON file holding an array of OMDb movies
+func LoadMovies(path string) ([]Movie, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var movies []Movie
+	if err = json.Unmarshal(data, &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// FailNext will answer the next n requests with the given HTTP status, before any other simulated failure
+func (s *Server) FailNext(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, status)
+	}
+}
+
+// Requests returns the query params of the requests served so far, the API key included
+func (s *Server) Requests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]url.Values(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	s.requests = append(s.requests, query)
+	served := len(s.requests)
+	failure := 0
+	if len(s.failures) > 0 {
+		failure, s.failures = s.failures[0], s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	if s.opts.Latency > 0 {
+		select {
+		case <-time.After(s.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch {
+	case failure != 0:
+		w.WriteHeader(failure)
+		return
+	case s.opts.FailEvery > 0 && served%s.opts.FailEvery == 0:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	case query.Get("apikey") == "":
+		s.error(w, http.StatusUnauthorized, "No API key provided.")
+		return
+	case s.opts.APIKey != "" && query.Get("apikey") != s.opts.APIKey:
+		s.error(w, http.StatusUnauthorized, "Invalid API key!")
+		return
+	case s.opts.RequestLimit > 0 && served > s.opts.RequestLimit:
+		s.error(w, http.StatusUnauthorized, "Request limit reached!")
+		return
+	}
+
+	switch {
+	case query.Get("i") != "":
+		s.byID(w, query)
+	case query.Get("t") != "":
+		s.byTitle(w, query)
+	case query.Get("s") != "":
+		s.search(w, query)
+	default:
+		s.error(w, http.StatusOK, "Incorrect IMDb ID.")
+	}
+}
+
+func (s *Server) byID(w http.ResponseWriter, query url.Values) {
+	id := query.Get("i")
+	if !imdbIDPattern.MatchString(id) {
+		s.error(w, http.StatusOK, "Incorrect IMDb ID.")
+		return
+	}
+
+	for _, m := range s.movies {
+		if m.ImdbID == id {
+			s.movie(w, m, query.Get("plot"))
+			return
+		}
+	}
+	s.error(w, http.StatusOK, "Incorrect IMDb ID.")
+}
+
+func (s *Server) byTitle(w http.ResponseWriter, query url.Values) {
+	for _, m := range s.movies {
+		if strings.EqualFold(m.Title, strings.TrimSpace(query.Get("t"))) && matches(m, query) {
+			s.movie(w, m, query.Get("plot"))
+			return
+		}
+	}
+	s.error(w, http.StatusOK, "Movie not found!")
+}
+
+func (s *Server) search(w http.ResponseWriter, query url.Values) {
+	term := strings.ToLower(strings.TrimSpace(query.Get("s")))
+	// OMDb refuses the searches matching too many titles to list
+	if len(term) < 3 {
+		s.error(w, http.StatusOK, "Too many results.")
+		return
+	}
+
+	page := 1
+	if p := query.Get("page"); p != "" {
+		var err error
+		page, err = strconv.Atoi(p)
+		if err != nil || page < 1 || page > maxPage {
+			s.error(w, http.StatusOK, "The offset specified in a OFFSET clause may not be negative.")
+			return
+		}
+	}
+
+	type summary struct {
+		Title  string `json:"Title"`
+		Year   string `json:"Year"`
+		ImdbID string `json:"imdbID"`
+		Type   string `json:"Type"`
+		Poster string `json:"Poster"`
+	}
+	var found []summary
+	for _, m := range s.movies {
+		if strings.Contains(strings.ToLower(m.Title), term) && matches(m, query) {
+			found = append(found, summary{m.Title, m.Year, m.ImdbID, m.Type, m.Poster})
+		}
+	}
+	if len(found) == 0 {
+		s.error(w, http.StatusOK, "Movie not found!")
+		return
+	}
+
+	start := (page - 1) * pageSize
+	if start >= len(found) {
+		s.error(w, http.StatusOK, "Movie not found!")
+		return
+	}
+	end := start + pageSize
+	if end > len(found) {
+		end = len(found)
+	}
+
+	s.write(w, http.StatusOK, map[string]interface{}{
+		"Search":       found[start:end],
+		"totalResults": strconv.Itoa(len(found)),
+		"Response":     "True",
+	})
+}
+
+// matches reports whether the movie passes the type and y filters of the query
+func matches(m Movie, query url.Values) bool {
+	if t := query.Get("type"); t != "" && !strings.EqualFold(m.Type, t) {
+		return false
+	}
+	if y := query.Get("y"); y != "" && !ranIn(m.Year, y) {
+		return false
+	}
+	return true
+}
+
+// ranIn reports whether year falls in the years of a movie, a series matches any year it ran.
+// The years of a series are a range such as "2005–2013", or "2005–" while it still runs
+func ranIn(years, year string) bool {
+	first, last := years, years
+	if i := strings.Index(years, "–"); i >= 0 {
+		first, last = years[:i], years[i+len("–"):]
+	}
+
+	y, err := strconv.Atoi(year)
+	from, errFrom := strconv.Atoi(first)
+	if err != nil || errFrom != nil {
+		return years == year
+	}
+	if last == "" {
+		return y >= from
+	}
+
+	to, err := strconv.Atoi(last)
+	return err == nil && y >= from && y <= to
+}
+
+func (s *Server) movie(w http.ResponseWriter, m Movie, plot string) {
+	if plot == string(domain.PlotShort) && m.ShortPlot != "" {
+		m.Plot = m.ShortPlot
+	}
+	m.ShortPlot = ""
+
+	s.write(w, http.StatusOK, struct {
+		Movie
+		Response string `json:"Response"`
+	}{m, "True"})
+}
+
+func (s *Server) error(w http.ResponseWriter, status int, message string) {
+	s.write(w, status, map[string]string{"Response": "False", "Error": message})
+}
+
+func (s *Server) write(w http.ResponseWriter, status int, payload interface{}) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
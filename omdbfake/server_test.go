@@ -0,0 +1,103 @@
+package omdbfake_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/omdbfake"
+)
+
+func get(t *testing.T, s *omdbfake.Server, query string) (int, map[string]interface{}) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?"+query, nil))
+
+	var body map[string]interface{}
+	if rec.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	}
+	return rec.Code, body
+}
+
+func TestByTitle(t *testing.T) {
+	s := omdbfake.New(omdbfake.Options{})
+
+	status, body := get(t, s, "apikey=k&t=the+office&type=series")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "tt0386676", body["imdbID"])
+	assert.Equal(t, "True", body["Response"])
+
+	// a series matches any year it ran, not only the first one
+	_, body = get(t, s, "apikey=k&t=the+office&y=2010")
+	assert.Equal(t, "tt0386676", body["imdbID"])
+
+	_, body = get(t, s, "apikey=k&t=the+office&y=2020")
+	assert.Equal(t, "Movie not found!", body["Error"])
+}
+
+func TestSearchYear(t *testing.T) {
+	s := omdbfake.New(omdbfake.Options{})
+
+	_, body := get(t, s, "apikey=k&s=batman&y=1994")
+	assert.Equal(t, "1", body["totalResults"])
+	assert.Equal(t, "tt0103359", body["Search"].([]interface{})[0].(map[string]interface{})["imdbID"])
+
+	_, body = get(t, s, "apikey=k&s=batman&y=1992")
+	assert.Equal(t, "2", body["totalResults"])
+}
+
+func TestSearchPage(t *testing.T) {
+	s := omdbfake.New(omdbfake.Options{})
+
+	_, body := get(t, s, "apikey=k&s=batman&page=2")
+	assert.Equal(t, "12", body["totalResults"])
+	assert.Len(t, body["Search"], 2)
+
+	_, body = get(t, s, "apikey=k&s=batman&page=3")
+	assert.Equal(t, "Movie not found!", body["Error"])
+
+	_, body = get(t, s, "apikey=k&s=batman&page=101")
+	assert.Equal(t, "False", body["Response"])
+}
+
+func TestFailures(t *testing.T) {
+	s := omdbfake.New(omdbfake.Options{FailEvery: 3})
+
+	status, _ := get(t, s, "apikey=k&i=tt0111161")
+	assert.Equal(t, http.StatusOK, status)
+
+	s.FailNext(1, http.StatusBadGateway)
+	status, _ = get(t, s, "apikey=k&i=tt0111161")
+	assert.Equal(t, http.StatusBadGateway, status)
+	status, _ = get(t, s, "apikey=k&i=tt0111161")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	status, body := get(t, s, "i=tt0111161")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "No API key provided.", body["Error"])
+	assert.Len(t, s.Requests(), 4)
+}
+
+func TestLoadMovies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "omdbfake")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "movies.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[{"Title":"Casablanca","Year":"1942","imdbID":"tt0034583","Type":"movie"}]`), 0600))
+
+	movies, err := omdbfake.LoadMovies(path)
+	require.NoError(t, err)
+	s := omdbfake.New(omdbfake.Options{Movies: movies})
+
+	_, body := get(t, s, "apikey=k&i=tt0034583")
+	assert.Equal(t, "Casablanca", body["Title"])
+	_, body = get(t, s, "apikey=k&i=tt0111161")
+	assert.Equal(t, "Incorrect IMDb ID.", body["Error"])
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type mysqlQuotaRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlQuotaRepository will create an implementation of domain.QuotaRepository
+func NewMysqlQuotaRepository(db *sql.DB) domain.QuotaRepository {
+	return &mysqlQuotaRepo{
+		DB: db,
+	}
+}
+
+// Used will return the usage of the provider on the given day, a day without calls has no row yet
+func (mq *mysqlQuotaRepo) Used(ctx context.Context, provider string, day time.Time) (used int64, err error) {
+	query := `SELECT used FROM api_quota WHERE provider = ? AND day = ?`
+	err = mq.DB.QueryRowContext(ctx, query, provider, day.Format("2006-01-02")).Scan(&used)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+
+	return
+}
+
+// Increment will atomically add one call to the usage of the provider on the given day and return the new usage.
+// LAST_INSERT_ID(expr) hands the updated counter back without a second query
+func (mq *mysqlQuotaRepo) Increment(ctx context.Context, provider string, day time.Time) (used int64, err error) {
+	query := `INSERT INTO api_quota (provider, day, used) VALUES (?, ?, LAST_INSERT_ID(1))
+		ON DUPLICATE KEY UPDATE used = LAST_INSERT_ID(used + 1)`
+	stmt, err := mq.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, provider, day.Format("2006-01-02"))
+	if err != nil {
+		return
+	}
+
+	return res.LastInsertId()
+}
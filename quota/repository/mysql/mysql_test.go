@@ -0,0 +1,52 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	repository "github.com/bxcodec/go-clean-arch/quota/repository/mysql"
+)
+
+func TestIncrement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO api_quota \\(provider, day, used\\) VALUES \\(\\?, \\?, LAST_INSERT_ID\\(1\\)\\)"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("omdb", "2020-05-01").WillReturnResult(sqlmock.NewResult(42, 2))
+
+	q := repository.NewMysqlQuotaRepository(db)
+
+	used, err := q.Increment(context.TODO(), "omdb", time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), used)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT used FROM api_quota WHERE provider = \\? AND day = \\?"
+	mock.ExpectQuery(query).WithArgs("omdb", "2020-05-01").WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(42))
+	mock.ExpectQuery(query).WithArgs("omdb", "2020-05-02").WillReturnRows(sqlmock.NewRows([]string{"used"}))
+
+	q := repository.NewMysqlQuotaRepository(db)
+
+	used, err := q.Used(context.TODO(), "omdb", time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), used)
+
+	used, err = q.Used(context.TODO(), "omdb", time.Date(2020, 5, 2, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), used)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
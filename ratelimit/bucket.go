@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket refills rate tokens per second up to burst tokens
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket will create a full TokenBucket, a burst lower than one is raised to one
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// Take will consume a token, when none is left it returns how long until the next one is available
+func (b *TokenBucket) Take() (ok bool, retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/logger"
+)
+
+// DailyCounter count calls per key and UTC day. The usage is persisted through a domain.QuotaRepository so
+// restarts don't reset it, and only counted by this process while the store is unavailable
+type DailyCounter struct {
+	store domain.QuotaRepository
+	now   func() time.Time
+
+	mu    sync.Mutex
+	day   time.Time
+	usage map[string]int64
+}
+
+// NewDailyCounter will create a DailyCounter, a nil store keeps the usage in memory only
+func NewDailyCounter(store domain.QuotaRepository) *DailyCounter {
+	return &DailyCounter{
+		store: store,
+		now:   time.Now,
+		usage: make(map[string]int64),
+	}
+}
+
+// Used will return the usage of key for the day without charging a call, along with the time left until the
+// usage resets
+func (d *DailyCounter) Used(ctx context.Context, key string) (used int64, resetIn time.Duration) {
+	day, resetIn := d.today()
+
+	local := d.usedLocal(key, day)
+	if d.store == nil {
+		return local, resetIn
+	}
+
+	used, err := d.store.Used(ctx, key, day)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("quota usage %s: %s", key, err)
+		return local, resetIn
+	}
+
+	d.syncLocal(key, day, used)
+	if local > used {
+		return local, resetIn
+	}
+	return used, resetIn
+}
+
+// Increment will add one call to the usage of key and return the usage of the day including this call,
+// along with the time left until the usage resets
+func (d *DailyCounter) Increment(ctx context.Context, key string) (used int64, resetIn time.Duration) {
+	day, resetIn := d.today()
+
+	local := d.countLocal(key, day)
+	if d.store == nil {
+		return local, resetIn
+	}
+
+	used, err := d.store.Increment(ctx, key, day)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("quota increment %s: %s", key, err)
+		return local, resetIn
+	}
+
+	d.syncLocal(key, day, used)
+	return used, resetIn
+}
+
+// today will return the UTC day the usage is counted for and the time left until it ends
+func (d *DailyCounter) today() (day time.Time, resetIn time.Duration) {
+	now := d.now().UTC()
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return day, day.AddDate(0, 0, 1).Sub(now)
+}
+
+func (d *DailyCounter) usedLocal(key string, day time.Time) int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.day.Equal(day) {
+		return 0
+	}
+	return d.usage[key]
+}
+
+func (d *DailyCounter) countLocal(key string, day time.Time) int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.day.Equal(day) {
+		d.day = day
+		d.usage = make(map[string]int64)
+	}
+	d.usage[key]++
+
+	return d.usage[key]
+}
+
+// syncLocal will raise the process local usage to the persisted one, so it stays accurate if the store goes away
+func (d *DailyCounter) syncLocal(key string, day time.Time, used int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.day.Equal(day) && used > d.usage[key] {
+		d.usage[key] = used
+	}
+}
@@ -0,0 +1,55 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain/mocks"
+	"github.com/bxcodec/go-clean-arch/ratelimit"
+)
+
+func TestTokenBucket(t *testing.T) {
+	b := ratelimit.NewTokenBucket(10, 2)
+
+	for i := 0; i < 2; i++ {
+		ok, _ := b.Take()
+		assert.True(t, ok)
+	}
+
+	ok, retryAfter := b.Take()
+	assert.False(t, ok)
+	assert.True(t, retryAfter > 0 && retryAfter <= 100*time.Millisecond)
+}
+
+func TestDailyCounter(t *testing.T) {
+	mockQuotaRepo := new(mocks.QuotaRepository)
+	mockQuotaRepo.On("Increment", mock.Anything, "omdb", mock.Anything).Return(int64(41), nil).Once()
+	mockQuotaRepo.On("Increment", mock.Anything, "omdb", mock.Anything).Return(int64(0), errors.New("Unexpected Error")).Once()
+	d := ratelimit.NewDailyCounter(mockQuotaRepo)
+
+	used, resetIn := d.Increment(context.TODO(), "omdb")
+	assert.Equal(t, int64(41), used)
+	assert.True(t, resetIn > 0 && resetIn <= 24*time.Hour)
+
+	// the store is unavailable, the usage keeps counting from the last persisted one
+	used, _ = d.Increment(context.TODO(), "omdb")
+	assert.Equal(t, int64(42), used)
+	mockQuotaRepo.AssertExpectations(t)
+
+	memory := ratelimit.NewDailyCounter(nil)
+	used, _ = memory.Increment(context.TODO(), "client:1")
+	assert.Equal(t, int64(1), used)
+	used, _ = memory.Increment(context.TODO(), "client:2")
+	assert.Equal(t, int64(1), used)
+
+	// reading the usage doesn't charge a call
+	used, _ = memory.Used(context.TODO(), "client:1")
+	assert.Equal(t, int64(1), used)
+	used, _ = memory.Used(context.TODO(), "client:3")
+	assert.Equal(t, int64(0), used)
+}
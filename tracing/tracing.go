@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the service spans
+const instrumentationName = "github.com/bxcodec/go-clean-arch"
+
+// propagator carries the span context across services in the W3C traceparent header
+var propagator = propagation.TraceContext{}
+
+// Options represent the settings of the tracer provider
+type Options struct {
+	ServiceName string
+	// SampleRatio is the share of new traces recorded, traces started elsewhere follow the caller decision
+	SampleRatio   float64
+	FlushInterval time.Duration
+}
+
+// NewTracerProvider will create a tracer provider exporting its spans in batches to the given exporter
+func NewTracerProvider(exporter sdktrace.SpanExporter, opts Options) *sdktrace.TracerProvider {
+	var batchOpts []sdktrace.BatchSpanProcessorOption
+	if opts.FlushInterval > 0 {
+		batchOpts = append(batchOpts, sdktrace.WithBatchTimeout(opts.FlushInterval))
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, batchOpts...),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(sdkresource.NewSchemaless(attribute.String("service.name", opts.ServiceName))),
+	)
+}
+
+// Start will create a span child of the span carried by ctx with the globally registered tracer provider,
+// the span is internal unless opts give another kind
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// Finish will record the error pointed by errp on the span then end it, it is meant to be deferred by functions
+// with a named error result
+func Finish(span trace.Span, errp *error) {
+	if errp != nil && *errp != nil {
+		span.RecordError(*errp)
+		span.SetStatus(codes.Error, (*errp).Error())
+	}
+	span.End()
+}
+
+// Inject will write the span context carried by ctx into the traceparent header
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract will return a copy of ctx carrying the span context of the traceparent header, if any,
+// the next span started from ctx becomes its child
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
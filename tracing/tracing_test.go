@@ -0,0 +1,82 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bxcodec/go-clean-arch/tracing"
+)
+
+func TestFinish(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := tracing.NewTracerProvider(exp, tracing.Options{ServiceName: "movie-api", SampleRatio: 1})
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	ctx, parent := tracing.Start(context.TODO(), "movieUsecase.GetByID")
+	_, child := tracing.Start(ctx, "omdb GET", trace.WithSpanKind(trace.SpanKindClient))
+	err := errors.New("Unexpected")
+	tracing.Finish(child, &err)
+	var ok error
+	tracing.Finish(parent, &ok)
+	require.NoError(t, tp.ForceFlush(context.TODO()))
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "omdb GET", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "Unexpected", spans[0].Status.Description)
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	assert.Equal(t, "movie-api", spans[1].Resource.Attributes()[0].Value.AsString())
+}
+
+func TestSampling(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := tracing.NewTracerProvider(exp, tracing.Options{SampleRatio: 0})
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	// a new trace is dropped but a sampled caller is followed
+	_, span := tracing.Start(context.TODO(), "movieUsecase.Fetch")
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span = tracing.Start(tracing.Extract(context.TODO(), header), "GET /movies")
+	assert.True(t, span.IsRecording())
+	span.End()
+
+	require.NoError(t, tp.ForceFlush(context.TODO()))
+	assert.Len(t, exp.GetSpans(), 1)
+}
+
+func TestPropagation(t *testing.T) {
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.Extract(context.TODO(), header)
+
+	sc := trace.SpanContextFromContext(ctx)
+	require.True(t, sc.IsValid())
+	assert.True(t, sc.IsRemote())
+	assert.True(t, sc.IsSampled())
+
+	out := http.Header{}
+	tracing.Inject(ctx, out)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", out.Get("traceparent"))
+
+	invalid := http.Header{}
+	invalid.Set("traceparent", "00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+	assert.False(t, trace.SpanContextFromContext(tracing.Extract(context.TODO(), invalid)).IsValid())
+}